@@ -0,0 +1,125 @@
+package fmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicMap is a read-mostly hash map for int64 keys with copy-on-write publishing.
+// Readers access an immutable FastMap snapshot loaded with a single atomic operation, so Get is wait-free
+// from any goroutine. Writers are serialized; each write clones the current snapshot, applies its mutations
+// and atomically publishes the result. The published snapshot SCN is used as the version number.
+type AtomicMap[T any] struct {
+	current atomic.Pointer[FastMap[T]]
+	mux     sync.Mutex // Serializes writers
+}
+
+// Get retrieves the value associated with the given key from the current snapshot.
+// It returns the value and a boolean indicating whether the key was found.
+func (a *AtomicMap[T]) Get(key int64) (T, bool) {
+	return a.current.Load().Get(key)
+}
+
+// Size returns the number of elements in the current snapshot.
+func (a *AtomicMap[T]) Size() int {
+	return a.current.Load().Size()
+}
+
+// Version returns the version of the current snapshot
+func (a *AtomicMap[T]) Version() int {
+	return a.current.Load().SCN()
+}
+
+// Snapshot returns the current snapshot; the returned map must not be modified.
+func (a *AtomicMap[T]) Snapshot() *FastMap[T] {
+	return a.current.Load()
+}
+
+// Update applies fn to a copy of the current snapshot and publishes it, returning the published version.
+func (a *AtomicMap[T]) Update(fn func(m *FastMap[T])) int {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	next := a.current.Load().Clone()
+	fn(next)
+	return a.publish(next)
+}
+
+// Commit applies all batch mutations to a copy of the current snapshot and publishes it, returning the published version.
+// The batch is reset afterwards and can be reused.
+func (a *AtomicMap[T]) Commit(batch *Batch[T]) int {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	next := a.current.Load().Clone()
+	for i, op := range batch.ops {
+		if op == batchDelete {
+			next.Delete(batch.keys[i])
+			continue
+		}
+		next.Put(batch.keys[i], batch.values[i])
+	}
+	batch.Reset()
+	return a.publish(next)
+}
+
+// publish bumps the snapshot SCN, so every published snapshot carries a distinct version, and stores it
+func (a *AtomicMap[T]) publish(next *FastMap[T]) int {
+	version := atomic.AddUint32(&next.scn, 1)
+	a.current.Store(next)
+	return int(version)
+}
+
+// NewBatch creates a batch of mutations to be committed to the map
+func (a *AtomicMap[T]) NewBatch() *Batch[T] {
+	return &Batch[T]{}
+}
+
+const (
+	batchPut uint8 = iota
+	batchDelete
+)
+
+// Batch represents a sequence of mutations applied together by AtomicMap.Commit
+type Batch[T any] struct {
+	ops    []uint8
+	keys   []int64
+	values []T
+}
+
+// Put records adding or updating the key with the value val.
+func (b *Batch[T]) Put(key int64, val T) {
+	b.ops = append(b.ops, batchPut)
+	b.keys = append(b.keys, key)
+	b.values = append(b.values, val)
+}
+
+// Delete records removing the key.
+func (b *Batch[T]) Delete(key int64) {
+	var zero T
+	b.ops = append(b.ops, batchDelete)
+	b.keys = append(b.keys, key)
+	b.values = append(b.values, zero)
+}
+
+// Len returns the number of recorded mutations
+func (b *Batch[T]) Len() int {
+	return len(b.ops)
+}
+
+// Reset removes all recorded mutations
+func (b *Batch[T]) Reset() {
+	var zero T
+	for i := range b.values {
+		b.values[i] = zero
+	}
+	b.ops = b.ops[:0]
+	b.keys = b.keys[:0]
+	b.values = b.values[:0]
+}
+
+// NewAtomicMap creates a new AtomicMap with the specified expected size, fill factor and options.
+func NewAtomicMap[T any](expectedSize int, fillFactor float64, opts ...Option) *AtomicMap[T] {
+	rejectOffHeap("AtomicMap", opts)
+	a := &AtomicMap[T]{}
+	a.current.Store(NewFastMap[T](expectedSize, fillFactor, opts...))
+	return a
+}
@@ -0,0 +1,85 @@
+package fmap
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestAtomicMap_Commit tests batched publishing of AtomicMap snapshots.
+func TestAtomicMap_Commit(t *testing.T) {
+	m := NewAtomicMap[int](4, 0.75)
+	batch := m.NewBatch()
+	for i := int64(1); i <= 100; i++ {
+		batch.Put(i, int(i))
+	}
+	before := m.Snapshot()
+	version := m.Commit(batch)
+	if version <= before.SCN() {
+		t.Errorf("Expected version > %d, got %d", before.SCN(), version)
+	}
+	if version != m.Version() {
+		t.Errorf("Expected version=%d, got %d", version, m.Version())
+	}
+	if before.Size() != 0 {
+		t.Errorf("Expected previous snapshot to stay unchanged, got size %d", before.Size())
+	}
+	if batch.Len() != 0 {
+		t.Errorf("Expected batch to be reset, got %d", batch.Len())
+	}
+	if m.Size() != 100 {
+		t.Errorf("Expected size=%d, got %d", 100, m.Size())
+	}
+
+	batch.Delete(1)
+	batch.Put(2, 200)
+	next := m.Commit(batch)
+	if next <= version {
+		t.Errorf("Expected version > %d, got %d", version, next)
+	}
+	if _, ok := m.Get(1); ok {
+		t.Errorf("Expected key 1 to be deleted")
+	}
+	if val, _ := m.Get(2); val != 200 {
+		t.Errorf("Expected value=%d, got %d", 200, val)
+	}
+	m.Update(func(fm *FastMap[int]) {
+		fm.Put(3, 300)
+	})
+	if val, _ := m.Get(3); val != 300 {
+		t.Errorf("Expected value=%d, got %d", 300, val)
+	}
+}
+
+// TestAtomicMap_ConcurrentReaders tests reading snapshots while a writer publishes.
+func TestAtomicMap_ConcurrentReaders(t *testing.T) {
+	m := NewAtomicMap[int64](16, 0.75)
+	var wg sync.WaitGroup
+	done := make(chan bool)
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				snapshot := m.Snapshot()
+				for i := int64(1); i <= int64(snapshot.Size()); i++ {
+					if val, ok := snapshot.Get(i); !ok || val != i {
+						t.Errorf("Expected key %d in snapshot", i)
+						return
+					}
+				}
+			}
+		}()
+	}
+	for i := int64(1); i <= 200; i++ {
+		m.Update(func(fm *FastMap[int64]) {
+			fm.Put(i, i)
+		})
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,77 @@
+package fmap
+
+// bulkBlock is the number of keys hashed and probed together by bulk operations
+const bulkBlock = 16
+
+// GetMany looks up all keys, storing values in out and presence flags in found, and returns the number of keys found.
+// out and found must be at least as long as keys.
+// Keys are processed in blocks with a probe cursor per unresolved key; every round advances all cursors of the block
+// by one slot, so cache misses of the block overlap instead of being serialized. Values are loaded only for matching
+// keys. Maps with group probing look keys up one by one.
+func (m *FastMap[T]) GetMany(keys []int64, out []T, found []bool) int {
+	_, _ = out[:len(keys)], found[:len(keys)]
+	var zero T
+	count := 0
+	if m.probing == groupProbing {
+		for i, key := range keys {
+			if out[i], found[i] = m.Get(key); found[i] {
+				count++
+			}
+		}
+		return count
+	}
+	robinHood := m.probing == robinHoodProbing
+	var pending [bulkBlock]int // Positions of unresolved keys in keys
+	var cursors [bulkBlock]int64
+	var dists [bulkBlock]int64 // Probe distances of cursors, used by Robin Hood early exit
+	for start := 0; start < len(keys); start += bulkBlock {
+		n := 0
+		for i := start; i < min(start+bulkBlock, len(keys)); i++ {
+			if keys[i] == FREE_KEY {
+				if out[i], found[i] = m.Get(FREE_KEY); found[i] {
+					count++
+				}
+				continue
+			}
+			pending[n], cursors[n], dists[n] = i, m.hash(keys[i])&m.mask, 0
+			n++
+		}
+		for n > 0 {
+			unresolved := 0
+			for j := 0; j < n; j++ {
+				i, ptr := pending[j], cursors[j]
+				switch k := m.keys[ptr]; {
+				case k == keys[i]:
+					out[i], found[i] = m.data[ptr], true
+					count++
+				case k == FREE_KEY || (robinHood && m.distance(k, ptr) < dists[j]):
+					out[i], found[i] = zero, false
+				default:
+					pending[unresolved], cursors[unresolved], dists[unresolved] = i, (ptr+1)&m.mask, dists[j]+1
+					unresolved++
+				}
+			}
+			n = unresolved
+		}
+	}
+	return count
+}
+
+// PutMany adds or updates keys with the corresponding values; vals must be at least as long as keys.
+// The map grows once for the final size instead of rehashing several times along the way.
+func (m *FastMap[T]) PutMany(keys []int64, vals []T) {
+	_ = vals[:len(keys)]
+	m.Reserve(m.size + len(keys))
+	for i, key := range keys {
+		if key == FREE_KEY {
+			m.putFreeKey(vals[i])
+			continue
+		}
+		ptr, found := m.locate(key)
+		if found {
+			m.data[ptr] = vals[i]
+			continue
+		}
+		m.insertAt(ptr, key, vals[i])
+	}
+}
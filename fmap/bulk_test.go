@@ -0,0 +1,124 @@
+package fmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestFastMap_GetMany(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[int](4, 0.75, mode.opts...)
+			keys := make([]int64, 100)
+			vals := make([]int, 100)
+			for i := range keys {
+				keys[i] = int64(i * 2)
+				vals[i] = i
+			}
+			m.PutMany(keys, vals)
+			assert.Equal(t, 100, m.Size())
+			assert.Equal(t, uint32(1), m.rehashes, "grows once")
+
+			lookup := make([]int64, 200)
+			for i := range lookup {
+				lookup[i] = int64(i)
+			}
+			out := make([]int, len(lookup))
+			found := make([]bool, len(lookup))
+			assert.Equal(t, 100, m.GetMany(lookup, out, found))
+			for i, key := range lookup {
+				assert.Equal(t, key%2 == 0 && key < 200, found[i], key)
+				if found[i] {
+					assert.Equal(t, int(key/2), out[i])
+				}
+			}
+			m.PutMany([]int64{0, 1}, []int{-1, -2})
+			assert.Equal(t, 101, m.Size())
+			assert.Equal(t, 2, m.GetMany([]int64{1, 0, 3}, out, found))
+			assert.Equal(t, []int{-2, -1, 0}, out[:3])
+		})
+	}
+}
+
+// TestFastMap_GetMany_Collisions verifies lookups that advance probe cursors over long runs of colliding keys.
+func TestFastMap_GetMany_Collisions(t *testing.T) {
+	identity := func(key int64) uint64 { return uint64(key) }
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[int64](64, 0.75, append([]Option{WithHasher("test-identity", identity)}, mode.opts...)...)
+			var lookup []int64
+			for i := int64(1); i <= 40; i++ {
+				m.Put(i*1024, i)
+				m.Put(i, -i)
+				lookup = append(lookup, i*1024, i*1024+512, i)
+			}
+			out := make([]int64, len(lookup))
+			found := make([]bool, len(lookup))
+			assert.Equal(t, 80, m.GetMany(lookup, out, found))
+			for i, key := range lookup {
+				val, ok := m.Get(key)
+				assert.Equal(t, ok, found[i], key)
+				assert.Equal(t, val, out[i], key)
+			}
+		})
+	}
+}
+
+// newBulkBenchmark returns a map too large for CPU caches and random keys to look up in batches of 512,
+// with enough keys that batches do not find their slots cached
+func newBulkBenchmark() (*FastMap[int64], []int64) {
+	const size = 1 << 22
+	m := NewFastMap[int64](size, 0.75)
+	for i := int64(1); i <= size; i++ {
+		m.Put(i, i)
+	}
+	keys := make([]int64, 1<<20)
+	for i := range keys {
+		keys[i] = rand.Int63n(size) + 1
+	}
+	return m, keys
+}
+
+func BenchmarkFastMap_GetMany(b *testing.B) {
+	m, keys := newBulkBenchmark()
+	const batch = 512
+	out := make([]int64, batch)
+	found := make([]bool, batch)
+	b.Run("Get", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			offset := i * batch & (len(keys) - 1)
+			for j, key := range keys[offset : offset+batch] {
+				out[j], found[j] = m.Get(key)
+			}
+		}
+	})
+	b.Run("GetMany", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			offset := i * batch & (len(keys) - 1)
+			m.GetMany(keys[offset:offset+batch], out, found)
+		}
+	})
+}
+
+func BenchmarkFastMap_PutMany(b *testing.B) {
+	keys := make([]int64, 1<<16)
+	vals := make([]int64, len(keys))
+	for i := range keys {
+		keys[i] = rand.Int63()
+	}
+	b.Run("Put", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m := NewFastMap[int64](16, 0.75)
+			for j, key := range keys {
+				m.Put(key, vals[j])
+			}
+		}
+	})
+	b.Run("PutMany", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m := NewFastMap[int64](16, 0.75)
+			m.PutMany(keys, vals)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+// Package cache provides a bounded LRU cache with optional expiry keyed by int64.
+package cache
+
+import (
+	"github.com/viant/gds/fmap"
+	"time"
+)
+
+// Reason describes why an entry left the cache
+type Reason uint8
+
+const (
+	Evicted Reason = iota // Entry was the least recently used one when the cache was full
+	Expired               // Entry outlived its time to live
+)
+
+// EvictFunc is called with every entry evicted or expired from the cache
+type EvictFunc[V any] func(key int64, value V, reason Reason)
+
+// Stats represents cache statistics
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+}
+
+// HitRatio returns the ratio of hits to all lookups
+func (s Stats) HitRatio() float64 {
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		return float64(s.Hits) / float64(lookups)
+	}
+	return 0
+}
+
+// entry represents a cached value with its expiry time in Unix nanoseconds, zero means no expiry
+type entry[V any] struct {
+	value    V
+	expireAt int64
+}
+
+// Cache is a bounded LRU cache keyed by int64 with optional per-entry time to live.
+// Entries live in fmap.OrderedMap parallel slices linked in recency order, so the cache holds no per-entry pointers.
+// Expired entries are removed when looked up, and Put checks a few least recently used entries for expiry.
+// This implementation is not safe for concurrent use.
+type Cache[V any] struct {
+	entries  *fmap.OrderedMap[entry[V]]
+	capacity int
+	options  *options
+	onEvict  EvictFunc[V]
+	stats    Stats
+}
+
+// OnEvict sets the function called with every evicted or expired entry
+func (c *Cache[V]) OnEvict(fn EvictFunc[V]) {
+	c.onEvict = fn
+}
+
+// Get retrieves the value associated with the given key and marks the key as most recently used.
+// It returns the value and a boolean indicating whether the key was found and not expired.
+func (c *Cache[V]) Get(key int64) (V, bool) {
+	var zero V
+	e, ok := c.entries.GetPointer(key)
+	if !ok {
+		c.stats.Misses++
+		return zero, false
+	}
+	if c.expired(e, c.options.now().UnixNano()) {
+		c.stats.Misses++
+		c.remove(key, Expired)
+		return zero, false
+	}
+	c.stats.Hits++
+	c.entries.MoveToBack(key)
+	return e.value, true
+}
+
+// Put adds or updates the key with the default time to live and marks the key as most recently used.
+func (c *Cache[V]) Put(key int64, value V) {
+	c.PutWithTTL(key, value, c.options.ttl)
+}
+
+// PutWithTTL adds or updates the key with the given time to live and marks the key as most recently used;
+// zero ttl disables expiry of the entry.
+// Adding a key to the full cache evicts the least recently used entry.
+func (c *Cache[V]) PutWithTTL(key int64, value V, ttl time.Duration) {
+	now := c.options.now().UnixNano()
+	e := entry[V]{value: value}
+	if ttl > 0 {
+		e.expireAt = now + int64(ttl)
+	}
+	if current, ok := c.entries.GetPointer(key); ok {
+		*current = e
+		c.entries.MoveToBack(key)
+		return
+	}
+	c.sweep(now, c.options.sweepLimit)
+	if c.entries.Size() >= c.capacity {
+		if oldest, _, ok := c.entries.First(); ok {
+			c.remove(oldest, Evicted)
+		}
+	}
+	c.entries.Put(key, e)
+}
+
+// Delete removes the key from the cache without calling the evict function.
+// It returns the removed value and a boolean indicating whether the key was found and not expired.
+func (c *Cache[V]) Delete(key int64) (V, bool) {
+	e, ok := c.entries.Delete(key)
+	if !ok || c.expired(&e, c.options.now().UnixNano()) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Sweep removes all expired entries and returns the number of removed entries.
+func (c *Cache[V]) Sweep() int {
+	return c.sweep(c.options.now().UnixNano(), c.entries.Size())
+}
+
+// Len returns the number of entries, including expired entries not removed yet.
+func (c *Cache[V]) Len() int {
+	return c.entries.Size()
+}
+
+// Capacity returns the maximum number of entries
+func (c *Cache[V]) Capacity() int {
+	return c.capacity
+}
+
+// Stats returns cache statistics
+func (c *Cache[V]) Stats() Stats {
+	return c.stats
+}
+
+// sweep checks up to limit least recently used entries and removes the expired ones
+func (c *Cache[V]) sweep(now int64, limit int) int {
+	removed := 0
+	next := c.entries.Iterator()
+	for i := 0; i < limit; i++ {
+		key, e, ok := next()
+		if !ok {
+			break
+		}
+		if c.expired(&e, now) {
+			c.remove(key, Expired)
+			removed++
+		}
+	}
+	return removed
+}
+
+// expired returns true if the entry expired at the given time
+func (c *Cache[V]) expired(e *entry[V], now int64) bool {
+	return e.expireAt != 0 && e.expireAt <= now
+}
+
+// remove removes the key, updates statistics and calls the evict function
+func (c *Cache[V]) remove(key int64, reason Reason) {
+	e, ok := c.entries.Delete(key)
+	if !ok {
+		return
+	}
+	switch reason {
+	case Evicted:
+		c.stats.Evictions++
+	case Expired:
+		c.stats.Expirations++
+	}
+	if c.onEvict != nil {
+		c.onEvict(key, e.value, reason)
+	}
+}
+
+// New creates a new Cache holding up to capacity entries.
+func New[V any](capacity int, opts ...Option) *Cache[V] {
+	if capacity <= 0 {
+		panic("Capacity must be positive")
+	}
+	options := newOptions(opts)
+	return &Cache[V]{
+		entries:  fmap.NewOrderedMap[entry[V]](capacity+1, 0.75, options.mapOptions...),
+		capacity: capacity,
+		options:  options,
+	}
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/gds/fmap"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestCache_LRU(t *testing.T) {
+	c := New[string](2)
+	var evicted []int64
+	c.OnEvict(func(key int64, value string, reason Reason) {
+		assert.Equal(t, Evicted, reason)
+		evicted = append(evicted, key)
+	})
+	c.Put(1, "a")
+	c.Put(2, "b")
+	val, ok := c.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "a", val)
+	c.Put(3, "c")
+	_, ok = c.Get(2)
+	assert.False(t, ok)
+	c.Put(1, "a2")
+	c.Put(4, "d")
+	assert.Equal(t, []int64{2, 3}, evicted)
+	val, ok = c.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "a2", val)
+	assert.Equal(t, 2, c.Len())
+	val, ok = c.Delete(4)
+	assert.True(t, ok)
+	assert.Equal(t, "d", val)
+	assert.Equal(t, Stats{Hits: 2, Misses: 1, Evictions: 2}, c.Stats())
+	assert.InDelta(t, 2.0/3, c.Stats().HitRatio(), 1e-9)
+}
+
+func TestCache_TTL(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	c := New[int](10, WithTTL(time.Minute), WithClock(clock.Now), WithSweepLimit(2))
+	var expired []int64
+	c.OnEvict(func(key int64, value int, reason Reason) {
+		assert.Equal(t, Expired, reason)
+		expired = append(expired, key)
+	})
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3)
+	c.PutWithTTL(4, 4, 0)
+	c.PutWithTTL(5, 5, time.Hour)
+
+	clock.now = clock.now.Add(2 * time.Minute)
+	_, ok := c.Get(3)
+	assert.False(t, ok)
+	c.Put(6, 6) // lazy sweep removes the two least recently used entries
+	assert.Equal(t, []int64{3, 1, 2}, expired)
+	assert.Equal(t, 3, c.Len())
+	assert.Equal(t, 0, c.Sweep())
+
+	clock.now = clock.now.Add(2 * time.Hour)
+	assert.Equal(t, 2, c.Sweep())
+	val, ok := c.Get(4)
+	assert.True(t, ok)
+	assert.Equal(t, 4, val)
+	assert.Equal(t, uint64(5), c.Stats().Expirations)
+}
+
+func TestCache_OffHeap(t *testing.T) {
+	assert.Panics(t, func() { New[int](4, WithMapOptions(fmap.WithOffHeap())) })
+}
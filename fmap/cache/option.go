@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"github.com/viant/gds/fmap"
+	"time"
+)
+
+// options represents Cache construction options
+type options struct {
+	ttl        time.Duration
+	now        func() time.Time
+	sweepLimit int
+	mapOptions []fmap.Option
+}
+
+// Option represents Cache construction option
+type Option func(o *options)
+
+// WithTTL returns an option setting the default time to live of entries added with Put; zero disables expiry.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithClock returns an option replacing time.Now as the source of the current time.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithSweepLimit returns an option setting how many least recently used entries Put checks for expiry.
+func WithSweepLimit(limit int) Option {
+	return func(o *options) {
+		o.sweepLimit = limit
+	}
+}
+
+// WithMapOptions returns an option passing FastMap options to the underlying index.
+// The index does not support fmap.WithOffHeap, New panics when given it.
+func WithMapOptions(opts ...fmap.Option) Option {
+	return func(o *options) {
+		o.mapOptions = opts
+	}
+}
+
+func newOptions(opts []Option) *options {
+	ret := &options{now: time.Now, sweepLimit: 4}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	return ret
+}
@@ -0,0 +1,545 @@
+package fmap
+
+import (
+	"fmt"
+	"github.com/viant/bintly"
+	"io"
+	"reflect"
+	"unsafe"
+)
+
+var writers = bintly.NewWriters()
+var readers = bintly.NewReaders()
+
+// codecVersion is the version of FastMap binary encoding, version 2 added probing strategy, version 3 added hasher
+const codecVersion uint8 = 3
+
+// EncodeBinary writes the map header and raw keys/data slots to the stream.
+func (m *FastMap[T]) EncodeBinary(stream *bintly.Writer) error {
+	stream.Uint8(codecVersion)
+	stream.Float64(m.fillFactor)
+	stream.Int(m.size)
+	stream.Uint32(m.scn)
+	stream.Bool(m.hasFreeKey)
+	stream.Uint8(uint8(m.probing))
+	if m.hasher == nil {
+		stream.Uint8(uint8(phiHasher))
+	} else {
+		stream.Uint8(uint8(m.hasher.kind))
+		stream.String(m.hasher.name)
+		stream.Uint64(m.hasher.seed)
+	}
+	stream.Int64s(m.keys)
+	if m.probing == groupProbing {
+		stream.Uint8s(m.ctrl)
+		stream.Int(m.tombstones)
+	}
+	if err := encodeValues(stream, m.data); err != nil {
+		return err
+	}
+	return encodeValues(stream, []T{m.freeVal})
+}
+
+// DecodeBinary reads the map from the stream.
+// Slots are restored as they were encoded, so no rehashing takes place.
+// The map is replaced only when the stream is valid, otherwise it is left unchanged.
+func (m *FastMap[T]) DecodeBinary(stream *bintly.Reader) error {
+	var version uint8
+	stream.Uint8(&version)
+	if version == 0 || version > codecVersion {
+		return fmt.Errorf("unsupported FastMap encoding version: %v", version)
+	}
+	decoded := FastMap[T]{id: mapIDs.Add(1)}
+	stream.Float64(&decoded.fillFactor)
+	stream.Int(&decoded.size)
+	stream.Uint32(&decoded.scn)
+	stream.Bool(&decoded.hasFreeKey)
+	if version >= 2 {
+		stream.Uint8((*uint8)(&decoded.probing))
+	}
+	if version >= 3 {
+		var kind hasherKind
+		stream.Uint8((*uint8)(&kind))
+		if kind != phiHasher {
+			var name string
+			var seed uint64
+			stream.String(&name)
+			stream.Uint64(&seed)
+			hasher, err := lookupHasher(kind, name, seed)
+			if err != nil {
+				return err
+			}
+			decoded.useHasher(hasher)
+		}
+	}
+	stream.Int64s(&decoded.keys)
+	if decoded.probing == groupProbing {
+		stream.Uint8s(&decoded.ctrl)
+		stream.Int(&decoded.tombstones)
+	}
+	data, err := decodeValues[T](stream)
+	if err != nil {
+		return err
+	}
+	freeVal, err := decodeValues[T](stream)
+	if err != nil {
+		return err
+	}
+	if len(freeVal) != 1 {
+		return fmt.Errorf("corrupted FastMap stream: missing FREE_KEY value")
+	}
+	decoded.data = data
+	decoded.freeVal = freeVal[0]
+	if err = decoded.validateSlots(); err != nil {
+		return err
+	}
+	// decoded slots live on the heap, so slots of an off-heap map are released
+	region := m.region
+	*m = decoded
+	releaseRegion(region)
+	return nil
+}
+
+// validateSlots checks decoded slots and sets capacity, mask and threshold.
+// Like a map built by inserts, the slots have to stay below the threshold, which leaves free slots ending every probe.
+func (m *FastMap[T]) validateSlots() error {
+	capacity := len(m.keys)
+	switch m.probing {
+	case linearProbing, robinHoodProbing:
+	case groupProbing:
+		if capacity < groupSize || len(m.ctrl) != capacity {
+			return fmt.Errorf("corrupted FastMap stream: invalid group capacity: %v", capacity)
+		}
+	default:
+		return fmt.Errorf("corrupted FastMap stream: unsupported probing: %v", m.probing)
+	}
+	if capacity < 2 || capacity&(capacity-1) != 0 || len(m.data) != capacity {
+		return fmt.Errorf("corrupted FastMap stream: invalid capacity: %v", capacity)
+	}
+	if !(m.fillFactor > 0 && m.fillFactor < 1) {
+		return fmt.Errorf("corrupted FastMap stream: invalid fill factor: %v", m.fillFactor)
+	}
+	slots := 0
+	for _, key := range m.keys {
+		if key != FREE_KEY {
+			slots++
+		}
+	}
+	occupied := slots
+	if m.hasFreeKey {
+		occupied++
+	}
+	if occupied != m.size {
+		return fmt.Errorf("corrupted FastMap stream: size %v does not match %v occupied slots", m.size, occupied)
+	}
+	if m.tombstones < 0 || m.tombstones > capacity-m.size {
+		return fmt.Errorf("corrupted FastMap stream: invalid tombstones: %v", m.tombstones)
+	}
+	m.setCapacity(capacity)
+	if slots+m.tombstones >= m.threshold {
+		return fmt.Errorf("corrupted FastMap stream: %v used slots reach threshold %v", slots+m.tombstones, m.threshold)
+	}
+	return nil
+}
+
+// WriteTo writes the binary encoded map to the writer.
+func (m *FastMap[T]) WriteTo(writer io.Writer) (int64, error) {
+	data, err := encodeBytes(m)
+	if err != nil {
+		return 0, err
+	}
+	n, err := writer.Write(data)
+	return int64(n), err
+}
+
+// encodeBytes returns the binary encoding of the encoder.
+// Only writers reset by Bytes go back to the pool; a failed encoding leaves partial state behind.
+func encodeBytes(encoder bintly.Encoder) ([]byte, error) {
+	buffer := writers.Get()
+	if err := encoder.EncodeBinary(buffer); err != nil {
+		return nil, err
+	}
+	data := buffer.Bytes()
+	writers.Put(buffer)
+	return data, nil
+}
+
+// ReadFrom reads the binary encoded map from the reader.
+func (m *FastMap[T]) ReadFrom(reader io.Reader) (int64, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	buffer := readers.Get()
+	defer readers.Put(buffer)
+	if err = buffer.FromBytes(data); err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), m.DecodeBinary(buffer)
+}
+
+// multiCodecVersion is the version of MultiMap binary encoding
+const multiCodecVersion uint8 = 1
+
+// EncodeBinary writes the key index, runs and arena of the map to the stream.
+func (m *MultiMap[T]) EncodeBinary(stream *bintly.Writer) error {
+	stream.Uint8(multiCodecVersion)
+	stream.Int(m.size)
+	stream.Int(m.garbage)
+	if err := m.index.EncodeBinary(stream); err != nil {
+		return err
+	}
+	if err := encodeValues(stream, m.runs); err != nil {
+		return err
+	}
+	return encodeValues(stream, m.arena)
+}
+
+// DecodeBinary reads the map from the stream.
+// The map is replaced only when the stream is valid, otherwise it is left unchanged.
+func (m *MultiMap[T]) DecodeBinary(stream *bintly.Reader) error {
+	var version uint8
+	stream.Uint8(&version)
+	if version == 0 || version > multiCodecVersion {
+		return fmt.Errorf("unsupported MultiMap encoding version: %v", version)
+	}
+	decoded := MultiMap[T]{index: &FastMap[multiHead]{}}
+	stream.Int(&decoded.size)
+	stream.Int(&decoded.garbage)
+	if err := decoded.index.DecodeBinary(stream); err != nil {
+		return err
+	}
+	runs, err := decodeValues[multiRun](stream)
+	if err != nil {
+		return err
+	}
+	arena, err := decodeValues[T](stream)
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		if run.offset < 0 || run.len < 0 || run.len > run.cap || int(run.offset)+int(run.cap) > len(arena) ||
+			run.next < multiNil || int(run.next) >= len(runs) {
+			return fmt.Errorf("corrupted MultiMap stream: invalid run: %+v", run)
+		}
+	}
+	decoded.runs = runs
+	decoded.arena = arena
+	if err = decoded.validateHeads(); err != nil {
+		return err
+	}
+	*m = decoded
+	return nil
+}
+
+// validateHeads checks that every key owns a distinct chain of runs ending at its last run and holding count values,
+// and that the counts add up to the map size
+func (m *MultiMap[T]) validateHeads() error {
+	owned := make([]bool, len(m.runs))
+	size := 0
+	var err error
+	m.heads(func(head *multiHead) {
+		if err != nil {
+			return
+		}
+		count, last := 0, multiNil
+		for r := head.first; r != multiNil; r = m.runs[r].next {
+			if r < 0 || int(r) >= len(m.runs) || owned[r] {
+				err = fmt.Errorf("corrupted MultiMap stream: invalid chain of runs: %+v", *head)
+				return
+			}
+			owned[r] = true
+			count += int(m.runs[r].len)
+			last = r
+		}
+		if head.count < 1 || count != int(head.count) || last != head.last {
+			err = fmt.Errorf("corrupted MultiMap stream: invalid chain of runs: %+v", *head)
+			return
+		}
+		size += count
+	})
+	if err == nil && size != m.size {
+		err = fmt.Errorf("corrupted MultiMap stream: size mismatch: expected %v, but had %v", size, m.size)
+	}
+	return err
+}
+
+// WriteTo writes the binary encoded map to the writer.
+func (m *MultiMap[T]) WriteTo(writer io.Writer) (int64, error) {
+	data, err := encodeBytes(m)
+	if err != nil {
+		return 0, err
+	}
+	n, err := writer.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom reads the binary encoded map from the reader.
+func (m *MultiMap[T]) ReadFrom(reader io.Reader) (int64, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	buffer := readers.Get()
+	defer readers.Put(buffer)
+	if err = buffer.FromBytes(data); err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), m.DecodeBinary(buffer)
+}
+
+// frozenCodecVersion is the version of FrozenMap binary encoding
+const frozenCodecVersion uint8 = 1
+
+// EncodeBinary writes the perfect hash and slots of the map to the stream.
+func (f *FrozenMap[T]) EncodeBinary(stream *bintly.Writer) error {
+	stream.Uint8(frozenCodecVersion)
+	stream.Uint64(f.seed)
+	stream.Bool(f.hasFreeKey)
+	stream.Uint32s(f.disp)
+	stream.Int64s(f.keys)
+	if err := encodeValues(stream, f.data); err != nil {
+		return err
+	}
+	return encodeValues(stream, []T{f.freeVal})
+}
+
+// DecodeBinary reads the map from the stream.
+func (f *FrozenMap[T]) DecodeBinary(stream *bintly.Reader) error {
+	var version uint8
+	stream.Uint8(&version)
+	if version == 0 || version > frozenCodecVersion {
+		return fmt.Errorf("unsupported FrozenMap encoding version: %v", version)
+	}
+	stream.Uint64(&f.seed)
+	stream.Bool(&f.hasFreeKey)
+	stream.Uint32s(&f.disp)
+	stream.Int64s(&f.keys)
+	data, err := decodeValues[T](stream)
+	if err != nil {
+		return err
+	}
+	freeVal, err := decodeValues[T](stream)
+	if err != nil {
+		return err
+	}
+	if len(data) != len(f.keys) || len(freeVal) != 1 {
+		return fmt.Errorf("corrupted FrozenMap stream: invalid size: %v", len(f.keys))
+	}
+	f.data = data
+	f.freeVal = freeVal[0]
+	return f.validate()
+}
+
+// WriteTo writes the binary encoded map to the writer.
+func (f *FrozenMap[T]) WriteTo(writer io.Writer) (int64, error) {
+	data, err := encodeBytes(f)
+	if err != nil {
+		return 0, err
+	}
+	n, err := writer.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom reads the binary encoded map from the reader.
+func (f *FrozenMap[T]) ReadFrom(reader io.Reader) (int64, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return int64(len(data)), err
+	}
+	buffer := readers.Get()
+	defer readers.Put(buffer)
+	if err = buffer.FromBytes(data); err != nil {
+		return int64(len(data)), err
+	}
+	return int64(len(data)), f.DecodeBinary(buffer)
+}
+
+// encodeValues writes values to the stream.
+// Primitive slices use bintly native encoding, T implementing bintly.Encoder is encoded one by one,
+// other pointer-free types are written as raw bytes.
+func encodeValues[T any](stream *bintly.Writer, values []T) error {
+	switch actual := any(values).(type) {
+	case []int:
+		stream.Ints(actual)
+	case []uint:
+		stream.Uints(actual)
+	case []int64:
+		stream.Int64s(actual)
+	case []uint64:
+		stream.Uint64s(actual)
+	case []int32:
+		stream.Int32s(actual)
+	case []uint32:
+		stream.Uint32s(actual)
+	case []int16:
+		stream.Int16s(actual)
+	case []uint16:
+		stream.Uint16s(actual)
+	case []int8:
+		stream.Int8s(actual)
+	case []uint8:
+		stream.Uint8s(actual)
+	case []float64:
+		stream.Float64s(actual)
+	case []float32:
+		stream.Float32s(actual)
+	case []bool:
+		stream.Bools(actual)
+	case []string:
+		stream.Strings(actual)
+	default:
+		if isEncoder[T]() {
+			return encodeCustom(stream, values)
+		}
+		rType := reflect.TypeOf(values).Elem()
+		if hasPointers(rType) {
+			return fmt.Errorf("unable to encode %v: type neither primitive, pointer-free nor bintly.Encoder", rType)
+		}
+		if rType.Size() == 0 {
+			stream.Alloc(int32(len(values)))
+			return nil
+		}
+		stream.Uint8s(rawBytes(values))
+	}
+	return nil
+}
+
+// decodeValues reads values encoded with encodeValues from the stream.
+func decodeValues[T any](stream *bintly.Reader) ([]T, error) {
+	var values []T
+	switch actual := any(&values).(type) {
+	case *[]int:
+		stream.Ints(actual)
+	case *[]uint:
+		stream.Uints(actual)
+	case *[]int64:
+		stream.Int64s(actual)
+	case *[]uint64:
+		stream.Uint64s(actual)
+	case *[]int32:
+		stream.Int32s(actual)
+	case *[]uint32:
+		stream.Uint32s(actual)
+	case *[]int16:
+		stream.Int16s(actual)
+	case *[]uint16:
+		stream.Uint16s(actual)
+	case *[]int8:
+		stream.Int8s(actual)
+	case *[]uint8:
+		stream.Uint8s(actual)
+	case *[]float64:
+		stream.Float64s(actual)
+	case *[]float32:
+		stream.Float32s(actual)
+	case *[]bool:
+		stream.Bools(actual)
+	case *[]string:
+		stream.Strings(actual)
+	default:
+		if isEncoder[T]() {
+			return decodeCustom[T](stream)
+		}
+		size := int(reflect.TypeOf(values).Elem().Size())
+		if size == 0 {
+			return make([]T, stream.Alloc()), nil
+		}
+		var raw []byte
+		stream.Uint8s(&raw)
+		values = make([]T, len(raw)/size)
+		copy(rawBytes(values), raw)
+	}
+	return values, nil
+}
+
+// isEncoder returns true if T or *T implements both bintly.Encoder and bintly.Decoder
+func isEncoder[T any]() bool {
+	var zero T
+	_, isEncoder := any(zero).(bintly.Encoder)
+	if !isEncoder {
+		_, isEncoder = any(&zero).(bintly.Encoder)
+	}
+	_, isDecoder := any(zero).(bintly.Decoder)
+	if !isDecoder {
+		_, isDecoder = any(&zero).(bintly.Decoder)
+	}
+	return isEncoder && isDecoder
+}
+
+// encodeCustom writes values with bintly.Encoder, pointer values are preceded by nil flag.
+func encodeCustom[T any](stream *bintly.Writer, values []T) error {
+	isPointer := reflect.TypeOf(values).Elem().Kind() == reflect.Ptr
+	stream.Alloc(int32(len(values)))
+	for i := range values {
+		if isPointer {
+			isNil := reflect.ValueOf(values[i]).IsNil()
+			stream.Bool(isNil)
+			if isNil {
+				continue
+			}
+		}
+		encoder, ok := any(values[i]).(bintly.Encoder)
+		if !ok {
+			encoder = any(&values[i]).(bintly.Encoder)
+		}
+		if err := encoder.EncodeBinary(stream); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeCustom reads values encoded with encodeCustom
+func decodeCustom[T any](stream *bintly.Reader) ([]T, error) {
+	values := make([]T, stream.Alloc())
+	rType := reflect.TypeOf(values).Elem()
+	isPointer := rType.Kind() == reflect.Ptr
+	for i := range values {
+		if isPointer {
+			var isNil bool
+			stream.Bool(&isNil)
+			if isNil {
+				continue
+			}
+			values[i] = reflect.New(rType.Elem()).Interface().(T)
+		}
+		decoder, ok := any(values[i]).(bintly.Decoder)
+		if !ok {
+			decoder = any(&values[i]).(bintly.Decoder)
+		}
+		if err := decoder.DecodeBinary(stream); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// rawBytes returns memory backing values as a byte slice
+func rawBytes[T any](values []T) []byte {
+	if len(values) == 0 {
+		return nil
+	}
+	var zero T
+	return unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(values))), len(values)*int(unsafe.Sizeof(zero)))
+}
+
+// hasPointers returns true if values of the type contain pointers
+func hasPointers(rType reflect.Type) bool {
+	switch rType.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Array:
+		return rType.Len() > 0 && hasPointers(rType.Elem())
+	case reflect.Struct:
+		for i := 0; i < rType.NumField(); i++ {
+			if hasPointers(rType.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
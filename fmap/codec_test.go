@@ -0,0 +1,174 @@
+package fmap
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/bintly"
+	"testing"
+)
+
+type codecPoint struct {
+	X, Y float32
+	ID   int64
+}
+
+type codecRecord struct {
+	Name string
+	Tags []string
+}
+
+func (r *codecRecord) EncodeBinary(stream *bintly.Writer) error {
+	stream.String(r.Name)
+	stream.Strings(r.Tags)
+	return nil
+}
+
+func (r *codecRecord) DecodeBinary(stream *bintly.Reader) error {
+	stream.String(&r.Name)
+	stream.Strings(&r.Tags)
+	return nil
+}
+
+// roundTrip encodes the map and decodes it into a new map
+func roundTrip[T any](t *testing.T, m *FastMap[T]) *FastMap[T] {
+	buffer := new(bytes.Buffer)
+	_, err := m.WriteTo(buffer)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	decoded := &FastMap[T]{}
+	_, err = decoded.ReadFrom(bytes.NewReader(buffer.Bytes()))
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	return decoded
+}
+
+func TestFastMap_WriteTo(t *testing.T) {
+	t.Run("int64", func(t *testing.T) {
+		m := NewFastMap[int64](4, 0.75)
+		for i := int64(0); i < 100; i++ {
+			m.Put(i, i*10)
+		}
+		decoded := roundTrip(t, m)
+		assert.Equal(t, m.Size(), decoded.Size())
+		assert.Equal(t, m.Cap(), decoded.Cap())
+		assert.Equal(t, m.SCN(), decoded.SCN())
+		assert.Equal(t, m.keys, decoded.keys)
+		for i := int64(0); i < 100; i++ {
+			val, ok := decoded.Get(i)
+			assert.True(t, ok)
+			assert.Equal(t, i*10, val)
+		}
+		decoded.Put(1000, 1)
+		val, _ := decoded.Get(1000)
+		assert.Equal(t, int64(1), val)
+	})
+	t.Run("string", func(t *testing.T) {
+		m := NewFastMap[string](4, 0.75)
+		m.Put(0, "zero")
+		m.Put(7, "seven")
+		decoded := roundTrip(t, m)
+		val, _ := decoded.Get(0)
+		assert.Equal(t, "zero", val)
+		val, _ = decoded.Get(7)
+		assert.Equal(t, "seven", val)
+	})
+	t.Run("pointer-free struct", func(t *testing.T) {
+		m := NewFastMap[codecPoint](4, 0.75)
+		m.Put(3, codecPoint{X: 1.5, Y: 2.5, ID: 3})
+		decoded := roundTrip(t, m)
+		val, _ := decoded.Get(3)
+		assert.Equal(t, codecPoint{X: 1.5, Y: 2.5, ID: 3}, val)
+	})
+	t.Run("encoder", func(t *testing.T) {
+		m := NewFastMap[codecRecord](4, 0.75)
+		m.Put(5, codecRecord{Name: "five", Tags: []string{"a", "b"}})
+		decoded := roundTrip(t, m)
+		val, _ := decoded.Get(5)
+		assert.Equal(t, codecRecord{Name: "five", Tags: []string{"a", "b"}}, val)
+	})
+	t.Run("encoder pointer", func(t *testing.T) {
+		m := NewFastMap[*codecRecord](4, 0.75)
+		m.Put(5, &codecRecord{Name: "five"})
+		decoded := roundTrip(t, m)
+		val, _ := decoded.Get(5)
+		assert.Equal(t, &codecRecord{Name: "five"}, val)
+	})
+	t.Run("robin hood", func(t *testing.T) {
+		m := NewFastMap[int](4, 0.9, WithRobinHood())
+		for i := int64(1); i <= 100; i++ {
+			m.Put(i<<16, int(i))
+		}
+		decoded := roundTrip(t, m)
+		assert.Equal(t, robinHoodProbing, decoded.probing)
+		for i := int64(1); i <= 100; i++ {
+			val, ok := decoded.Get(i << 16)
+			assert.True(t, ok)
+			assert.Equal(t, int(i), val)
+		}
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		m := NewFastMap[[]int](4, 0.75)
+		_, err := m.WriteTo(new(bytes.Buffer))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestFastMap_DecodeBinary_Corrupted(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    []Option
+		corrupt func(m *FastMap[int])
+	}{
+		{name: "zero fill factor", corrupt: func(m *FastMap[int]) { m.fillFactor = 0 }},
+		{name: "fill factor one", corrupt: func(m *FastMap[int]) { m.fillFactor = 1 }},
+		{name: "size", corrupt: func(m *FastMap[int]) { m.size++ }},
+		{name: "free key", corrupt: func(m *FastMap[int]) { m.hasFreeKey = true }},
+		{name: "full table", corrupt: func(m *FastMap[int]) {
+			for i := range m.keys {
+				m.keys[i] = int64(i + 1)
+			}
+			m.size = len(m.keys)
+		}},
+		{name: "threshold", corrupt: func(m *FastMap[int]) {
+			for i := 0; i < m.threshold; i++ {
+				m.keys[i] = int64(i + 1)
+			}
+			m.size = m.threshold
+		}},
+		{name: "probing", corrupt: func(m *FastMap[int]) { m.probing = 7 }},
+		{name: "group capacity", opts: []Option{WithGroupProbing()}, corrupt: func(m *FastMap[int]) {
+			m.keys, m.data, m.ctrl, m.size = make([]int64, 4), make([]int, 4), newCtrl(4), 0
+		}},
+		{name: "negative tombstones", opts: []Option{WithGroupProbing()}, corrupt: func(m *FastMap[int]) { m.tombstones = -1 }},
+		{name: "tombstones", opts: []Option{WithGroupProbing()}, corrupt: func(m *FastMap[int]) { m.tombstones = len(m.keys) }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewFastMap[int](4, 0.75, tc.opts...)
+			m.Put(1, 1)
+			m.Put(2, 2)
+			tc.corrupt(m)
+			buffer := new(bytes.Buffer)
+			_, err := m.WriteTo(buffer)
+			assert.Nil(t, err)
+
+			receiver := NewFastMap[int](4, 0.75)
+			receiver.Put(5, 5)
+			_, err = receiver.ReadFrom(bytes.NewReader(buffer.Bytes()))
+			if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), "corrupted FastMap stream")
+			}
+			for i := int64(10); i < 100; i++ {
+				receiver.Put(i, int(i))
+			}
+			val, ok := receiver.Get(5)
+			assert.True(t, ok, "receiver is left unchanged")
+			assert.Equal(t, 5, val)
+			assert.Equal(t, 91, receiver.Size())
+			_, ok = receiver.Get(1)
+			assert.False(t, ok)
+		})
+	}
+}
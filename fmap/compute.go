@@ -0,0 +1,161 @@
+package fmap
+
+import "sync/atomic"
+
+// locate returns the slot holding the given non FREE_KEY key, or the slot where the key is to be inserted if absent.
+func (m *FastMap[T]) locate(key int64) (int64, bool) {
+	switch m.probing {
+	case robinHoodProbing:
+		return m.locateRobinHood(key)
+	case groupProbing:
+		return m.locateGroup(key)
+	}
+	ptr := m.hash(key) & m.mask
+	for {
+		k := m.keys[ptr]
+		if k == key {
+			return ptr, true
+		}
+		if k == FREE_KEY {
+			return ptr, false
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+}
+
+// insertAt adds the non FREE_KEY key at the slot returned by locate and reports whether the insertion moved
+// existing entries to other slots, either by a Robin Hood shift or by a rehash.
+func (m *FastMap[T]) insertAt(ptr int64, key int64, val T) bool {
+	rehashes := m.rehashes
+	moved := false
+	switch m.probing {
+	case robinHoodProbing:
+		moved = m.insertRobinHood(ptr, key, val)
+	case groupProbing:
+		m.insertGroup(ptr, key, val)
+	default:
+		atomic.AddUint32(&m.scn, 1) //added new key
+		m.keys[ptr] = key
+		m.data[ptr] = val
+		m.size++
+		if m.size >= m.threshold {
+			m.rehash()
+		}
+	}
+	return moved || m.rehashes != rehashes
+}
+
+// removeAt removes the entry at the occupied slot ptr.
+func (m *FastMap[T]) removeAt(ptr int64) {
+	switch m.probing {
+	case robinHoodProbing:
+		m.removeRobinHood(ptr)
+	case groupProbing:
+		m.removeGroup(ptr)
+	default:
+		m.shiftBackward(ptr)
+	}
+	m.size--
+	atomic.AddUint32(&m.scn, 1) //removed key
+}
+
+// putFreeKey adds or updates FREE_KEY value.
+func (m *FastMap[T]) putFreeKey(val T) {
+	if !m.hasFreeKey {
+		m.size++
+		m.hasFreeKey = true
+		atomic.AddUint32(&m.scn, 1) //added new key
+	}
+	m.freeVal = val
+}
+
+// removeFreeKey removes FREE_KEY value.
+func (m *FastMap[T]) removeFreeKey() {
+	var zero T
+	m.hasFreeKey = false
+	m.freeVal = zero
+	m.size--
+	atomic.AddUint32(&m.scn, 1) //removed key
+}
+
+// GetOrInsert returns the pointer to the value associated with the key, inserting the value returned by fn
+// when the key is absent, with a single probe.
+// It returns the pointer, a boolean indicating whether the key was found, and a boolean indicating whether
+// the insertion moved existing entries, which invalidates pointers returned earlier by GetPointer.
+// fn must not modify the map.
+func (m *FastMap[T]) GetOrInsert(key int64, fn func() T) (*T, bool, bool) {
+	if key == FREE_KEY {
+		if m.hasFreeKey {
+			return &m.freeVal, true, false
+		}
+		m.putFreeKey(fn())
+		return &m.freeVal, false, false
+	}
+	ptr, found := m.locate(key)
+	if found {
+		return &m.data[ptr], true, false
+	}
+	if moved := m.insertAt(ptr, key, fn()); moved {
+		return &m.data[m.find(key)], false, true
+	}
+	return &m.data[ptr], false, false
+}
+
+// Upsert adds or updates the key with the value val with a single probe.
+// It returns the previous value, a boolean indicating whether the value was replaced, and a boolean indicating
+// whether the insertion moved existing entries, which invalidates pointers returned earlier by GetPointer.
+func (m *FastMap[T]) Upsert(key int64, val T) (T, bool, bool) {
+	var zero T
+	if key == FREE_KEY {
+		old, replaced := m.freeVal, m.hasFreeKey
+		m.putFreeKey(val)
+		if !replaced {
+			old = zero
+		}
+		return old, replaced, false
+	}
+	ptr, found := m.locate(key)
+	if found {
+		old := m.data[ptr]
+		m.data[ptr] = val
+		return old, true, false
+	}
+	return zero, false, m.insertAt(ptr, key, val)
+}
+
+// Compute updates the value associated with the key with the result of fn with a single probe.
+// fn receives the current value and a boolean indicating whether the key was found, and returns the new value
+// and a boolean indicating whether the key is to be kept; a key that is not kept is removed or not inserted.
+// It returns the resulting value, a boolean indicating whether the key is present, and a boolean indicating
+// whether the insertion moved existing entries, which invalidates pointers returned earlier by GetPointer.
+// fn must not modify the map.
+func (m *FastMap[T]) Compute(key int64, fn func(old T, found bool) (T, bool)) (T, bool, bool) {
+	var zero T
+	if key == FREE_KEY {
+		val, keep := fn(m.freeVal, m.hasFreeKey)
+		if keep {
+			m.putFreeKey(val)
+			return val, true, false
+		}
+		if m.hasFreeKey {
+			m.removeFreeKey()
+		}
+		return zero, false, false
+	}
+	ptr, found := m.locate(key)
+	var old T
+	if found {
+		old = m.data[ptr]
+	}
+	val, keep := fn(old, found)
+	switch {
+	case keep && found:
+		m.data[ptr] = val
+		return val, true, false
+	case keep:
+		return val, true, m.insertAt(ptr, key, val)
+	case found:
+		m.removeAt(ptr)
+	}
+	return zero, false, false
+}
@@ -0,0 +1,119 @@
+package fmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// probingModes lists FastMap options for every probing strategy
+var probingModes = []struct {
+	name string
+	opts []Option
+}{
+	{name: "Linear"},
+	{name: "RobinHood", opts: []Option{WithRobinHood()}},
+	{name: "Group", opts: []Option{WithGroupProbing()}},
+}
+
+func TestFastMap_GetOrInsert(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[int64](4, 0.75, mode.opts...)
+			moves := 0
+			for _, key := range []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10} {
+				ptr, found, moved := m.GetOrInsert(key, func() int64 { return key * 10 })
+				assert.False(t, found)
+				assert.Equal(t, key*10, *ptr)
+				if moved {
+					moves++
+				}
+				*ptr += 1
+			}
+			assert.Greater(t, moves, 0)
+			for _, key := range []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10} {
+				ptr, found, moved := m.GetOrInsert(key, func() int64 {
+					t.Fatalf("unexpected insert of %d", key)
+					return 0
+				})
+				assert.True(t, found)
+				assert.False(t, moved)
+				assert.Equal(t, key*10+1, *ptr)
+			}
+			assert.Equal(t, 11, m.Size())
+		})
+	}
+}
+
+// TestFastMap_GetOrInsert_RobinHoodShift verifies that an insertion shifting entries reports moved entries.
+func TestFastMap_GetOrInsert_RobinHoodShift(t *testing.T) {
+	identity := func(key int64) uint64 { return uint64(key) }
+	m := NewFastMap[int64](8, 0.75, WithRobinHood(), WithHasher("test-identity", identity))
+	assert.Equal(t, 16, m.Cap())
+	m.Put(2, 2)
+	m.Put(3, 3)
+	p, ok := m.GetPointer(3)
+	assert.True(t, ok)
+	ptr, found, moved := m.GetOrInsert(18, func() int64 { return 18 })
+	assert.False(t, found)
+	assert.True(t, moved, "key 3 shifted to make room for key 18")
+	assert.Equal(t, int64(18), *ptr)
+	assert.Equal(t, int64(18), *p, "stale pointer reads the entry now occupying the slot")
+	val, _ := m.Get(3)
+	assert.Equal(t, int64(3), val)
+
+	_, found, moved = m.GetOrInsert(5, func() int64 { return 5 })
+	assert.False(t, found)
+	assert.False(t, moved)
+}
+
+func TestFastMap_Upsert(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[string](4, 0.75, mode.opts...)
+			for _, key := range []int64{0, 17} {
+				old, replaced, _ := m.Upsert(key, "a")
+				assert.False(t, replaced)
+				assert.Equal(t, "", old)
+				old, replaced, moved := m.Upsert(key, "b")
+				assert.True(t, replaced)
+				assert.False(t, moved)
+				assert.Equal(t, "a", old)
+				val, _ := m.Get(key)
+				assert.Equal(t, "b", val)
+			}
+			assert.Equal(t, 2, m.Size())
+		})
+	}
+}
+
+func TestFastMap_Compute(t *testing.T) {
+	increment := func(old int, found bool) (int, bool) { return old + 1, true }
+	remove := func(old int, found bool) (int, bool) { return 0, false }
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[int](4, 0.75, mode.opts...)
+			for i := 0; i < 3; i++ {
+				for key := int64(0); key < 100; key++ {
+					val, kept, _ := m.Compute(key, increment)
+					assert.True(t, kept)
+					assert.Equal(t, i+1, val)
+				}
+			}
+			assert.Equal(t, 100, m.Size())
+			for key := int64(0); key < 100; key += 2 {
+				_, kept, _ := m.Compute(key, remove)
+				assert.False(t, kept)
+			}
+			_, kept, _ := m.Compute(1000, remove)
+			assert.False(t, kept)
+			assert.Equal(t, 50, m.Size())
+			for key := int64(0); key < 100; key++ {
+				val, ok := m.Get(key)
+				assert.Equal(t, key%2 == 1, ok)
+				if ok {
+					assert.Equal(t, 3, val)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package fmap
+
+import (
+	"container/heap"
+	"golang.org/x/exp/constraints"
+	"sort"
+)
+
+// Number is a constraint for CounterMap values
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// Counter represents a key with its counter value
+type Counter[N Number] struct {
+	Key   int64
+	Value N
+}
+
+// CounterMap is a hash map of int64 keys to numeric counters built on FastMap.
+// Add locates or inserts the key with a single probe, so it costs no more than a Put.
+// This implementation is not safe for concurrent use.
+type CounterMap[N Number] struct {
+	m *FastMap[N]
+}
+
+// Add adds delta to the key counter, starting from zero for a new key, and returns the updated counter.
+func (c *CounterMap[N]) Add(key int64, delta N) N {
+	m := c.m
+	if key == FREE_KEY {
+		m.putFreeKey(m.freeVal + delta)
+		return m.freeVal
+	}
+	ptr, found := m.locate(key)
+	if found {
+		m.data[ptr] += delta
+		return m.data[ptr]
+	}
+	m.insertAt(ptr, key, delta)
+	return delta
+}
+
+// Inc increments the key counter by one and returns the updated counter.
+func (c *CounterMap[N]) Inc(key int64) N {
+	return c.Add(key, 1)
+}
+
+// Get returns the key counter and a boolean indicating whether the key was found.
+func (c *CounterMap[N]) Get(key int64) (N, bool) {
+	return c.m.Get(key)
+}
+
+// Delete removes the key counter.
+func (c *CounterMap[N]) Delete(key int64) (N, bool) {
+	return c.m.Delete(key)
+}
+
+// Size returns the number of counters.
+func (c *CounterMap[N]) Size() int {
+	return c.m.Size()
+}
+
+// Map returns the underlying FastMap
+func (c *CounterMap[N]) Map() *FastMap[N] {
+	return c.m
+}
+
+// each calls fn for every counter
+func (c *CounterMap[N]) each(fn func(key int64, value N)) {
+	m := c.m
+	if m.hasFreeKey {
+		fn(FREE_KEY, m.freeVal)
+	}
+	for i, key := range m.keys {
+		if key != FREE_KEY {
+			fn(key, m.data[i])
+		}
+	}
+}
+
+// Sum returns the sum of all counters.
+func (c *CounterMap[N]) Sum() N {
+	var sum N
+	c.each(func(key int64, value N) {
+		sum += value
+	})
+	return sum
+}
+
+// Merge adds all counters of the other map to this map.
+func (c *CounterMap[N]) Merge(other *CounterMap[N]) {
+	other.each(func(key int64, value N) {
+		c.Add(key, value)
+	})
+}
+
+// TopK returns up to k counters with the highest values, ordered by value descending, then by key ascending.
+func (c *CounterMap[N]) TopK(k int) []Counter[N] {
+	if k <= 0 {
+		return nil
+	}
+	top := make(counterHeap[N], 0, k)
+	c.each(func(key int64, value N) {
+		counter := Counter[N]{Key: key, Value: value}
+		if len(top) < k {
+			heap.Push(&top, counter)
+			return
+		}
+		if top.less(top[0], counter) {
+			top[0] = counter
+			heap.Fix(&top, 0)
+		}
+	})
+	result := []Counter[N](top)
+	sort.Slice(result, func(i, j int) bool {
+		return top.less(result[j], result[i])
+	})
+	return result
+}
+
+// counterHeap is a min heap of counters
+type counterHeap[N Number] []Counter[N]
+
+// less orders counters by value, and by key descending for equal values
+func (h counterHeap[N]) less(a, b Counter[N]) bool {
+	if a.Value != b.Value {
+		return a.Value < b.Value
+	}
+	return a.Key > b.Key
+}
+
+func (h counterHeap[N]) Len() int           { return len(h) }
+func (h counterHeap[N]) Less(i, j int) bool { return h.less(h[i], h[j]) }
+func (h counterHeap[N]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *counterHeap[N]) Push(x any)        { *h = append(*h, x.(Counter[N])) }
+func (h *counterHeap[N]) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// NewCounterMap creates a new CounterMap with the specified expected size, fill factor and options.
+func NewCounterMap[N Number](expectedSize int, fillFactor float64, opts ...Option) *CounterMap[N] {
+	rejectOffHeap("CounterMap", opts)
+	return &CounterMap[N]{m: NewFastMap[N](expectedSize, fillFactor, opts...)}
+}
@@ -0,0 +1,50 @@
+package fmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCounterMap(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			c := NewCounterMap[int](4, 0.75, mode.opts...)
+			for key := int64(0); key < 100; key++ {
+				for i := int64(0); i <= key%10; i++ {
+					c.Inc(key)
+				}
+			}
+			assert.Equal(t, 100, c.Size())
+			assert.Equal(t, 550, c.Sum())
+			assert.Equal(t, 8, c.Add(7, 0))
+			assert.Equal(t, 18, c.Add(7, 10))
+			val, ok := c.Get(7)
+			assert.True(t, ok)
+			assert.Equal(t, 18, val)
+
+			top := c.TopK(3)
+			assert.Equal(t, []Counter[int]{{Key: 7, Value: 18}, {Key: 9, Value: 10}, {Key: 19, Value: 10}}, top)
+
+			other := NewCounterMap[int](4, 0.75)
+			other.Add(0, 5)
+			other.Add(1000, 7)
+			c.Merge(other)
+			val, _ = c.Get(0)
+			assert.Equal(t, 6, val)
+			val, _ = c.Get(1000)
+			assert.Equal(t, 7, val)
+			assert.Equal(t, 101, c.Size())
+		})
+	}
+}
+
+func TestCounterMap_Float(t *testing.T) {
+	c := NewCounterMap[float64](4, 0.75)
+	c.Add(1, 0.5)
+	c.Add(1, 0.25)
+	c.Add(2, 2)
+	assert.Equal(t, 2.75, c.Sum())
+	assert.Equal(t, []Counter[float64]{{Key: 2, Value: 2}}, c.TopK(1))
+	assert.Nil(t, c.TopK(0))
+	assert.Len(t, c.TopK(5), 2)
+}
@@ -0,0 +1,58 @@
+package fmap
+
+import "sync/atomic"
+
+// Diff compares the map with its older version, typically a Clone taken earlier, and returns keys added to the map,
+// keys removed from it and keys whose values changed according to eq, each in no particular order.
+// Values are not compared when eq is nil.
+// When old is an unmodified Clone of the map and the map SCN is unchanged since then, no keys were added or removed,
+// so Diff returns immediately if eq is nil.
+func (m *FastMap[T]) Diff(old *FastMap[T], eq func(a, b T) bool) (added, removed, changed []int64) {
+	if eq == nil && m.unchangedSince(old) {
+		return nil, nil, nil
+	}
+	if m.hasFreeKey && old.hasFreeKey {
+		if eq != nil && !eq(old.freeVal, m.freeVal) {
+			changed = append(changed, FREE_KEY)
+		}
+	} else if m.hasFreeKey {
+		added = append(added, FREE_KEY)
+	} else if old.hasFreeKey {
+		removed = append(removed, FREE_KEY)
+	}
+	sameLayout := len(m.keys) == len(old.keys)
+	for i, key := range m.keys {
+		if key == FREE_KEY {
+			continue
+		}
+		// the same key in the same slot spares the lookup, which is the case for all keys while the layout is unchanged
+		ptr := int64(i)
+		if !sameLayout || old.keys[i] != key {
+			if ptr = old.find(key); ptr < 0 {
+				added = append(added, key)
+				continue
+			}
+		}
+		if eq != nil && !eq(old.data[ptr], m.data[i]) {
+			changed = append(changed, key)
+		}
+	}
+	for i, key := range old.keys {
+		if key == FREE_KEY || (sameLayout && m.keys[i] == key) {
+			continue
+		}
+		if m.find(key) < 0 {
+			removed = append(removed, key)
+		}
+	}
+	return added, removed, changed
+}
+
+// unchangedSince reports whether old is a Clone of the map and neither map had keys added or removed since cloning
+func (m *FastMap[T]) unchangedSince(old *FastMap[T]) bool {
+	if m.id == 0 || old.cloneOf != m.id {
+		return false
+	}
+	scn := atomic.LoadUint32(&m.scn)
+	return scn == old.cloneSCN && atomic.LoadUint32(&old.scn) == scn && len(m.keys) == len(old.keys)
+}
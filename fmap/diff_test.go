@@ -0,0 +1,93 @@
+package fmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+)
+
+func sortedKeys(keys []int64) []int64 {
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func TestFastMap_Clone(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[int](4, 0.75, mode.opts...)
+			for i := int64(0); i < 50; i++ {
+				m.Put(i, int(i))
+			}
+			c := m.Clone()
+			assert.Equal(t, m.SCN(), c.SCN())
+			m.Put(100, 100)
+			m.Put(1, -1)
+			assert.Equal(t, 50, c.Size())
+			val, _ := c.Get(1)
+			assert.Equal(t, 1, val)
+			_, ok := c.Get(100)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestFastMap_Diff(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[int](64, 0.75, mode.opts...)
+			for i := int64(0); i < 40; i++ {
+				m.Put(i, int(i))
+			}
+			old := m.Clone()
+			added, removed, changed := m.Diff(old, eq)
+			assert.Empty(t, added)
+			assert.Empty(t, removed)
+			assert.Empty(t, changed)
+
+			m.Put(3, 30)
+			m.Put(0, -1)
+			added, removed, changed = m.Diff(old, nil)
+			assert.Empty(t, added)
+			assert.Empty(t, removed)
+			assert.Empty(t, changed)
+			_, _, changed = m.Diff(old, eq)
+			assert.Equal(t, []int64{0, 3}, sortedKeys(changed))
+
+			m.Delete(5)
+			m.Delete(0)
+			for i := int64(100); i < 200; i++ { // grows the map, so slots no longer line up
+				m.Put(i, int(i))
+			}
+			added, removed, changed = m.Diff(old, eq)
+			assert.Equal(t, 100, len(added))
+			assert.Equal(t, []int64{0, 5}, sortedKeys(removed))
+			assert.Equal(t, []int64{3}, changed)
+			added, removed, _ = old.Diff(m, eq)
+			assert.Equal(t, []int64{0, 5}, sortedKeys(added))
+			assert.Equal(t, 100, len(removed))
+		})
+	}
+}
+
+// TestFastMap_Diff_Lineage verifies that maps with equal SCNs are compared unless one is an unmodified Clone of the other.
+func TestFastMap_Diff_Lineage(t *testing.T) {
+	a := NewFastMap[int](64, 0.75)
+	b := NewFastMap[int](64, 0.75)
+	a.Put(1, 1)
+	b.Put(2, 2)
+	assert.Equal(t, a.SCN(), b.SCN())
+	added, removed, _ := a.Diff(b, nil)
+	assert.Equal(t, []int64{1}, added)
+	assert.Equal(t, []int64{2}, removed)
+
+	old := a.Clone()
+	assert.True(t, a.unchangedSince(old))
+	assert.False(t, old.unchangedSince(a))
+	a.Put(7, 7)
+	old.Put(8, 8)
+	assert.Equal(t, a.SCN(), old.SCN())
+	added, removed, _ = a.Diff(old, nil)
+	assert.Equal(t, []int64{7}, added)
+	assert.Equal(t, []int64{8}, removed)
+}
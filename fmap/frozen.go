@@ -0,0 +1,231 @@
+package fmap
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+)
+
+const (
+	frozenBucketSize        = 4          // Average number of keys per bucket
+	frozenDirect     uint32 = 1 << 31    // Displacement flag of a singleton bucket holding its slot directly
+	frozenMaxDisp    uint32 = 1 << 20    // Displacements tried per bucket before the build restarts with another seed
+	frozenSeed       uint64 = 0x2F3E1D9B // Seed of the first build attempt, so builds are reproducible
+)
+
+// FrozenMap is an immutable hash map of int64 keys backed by a minimal perfect hash.
+// Keys are spread over buckets of about frozenBucketSize keys with the CHD algorithm: each bucket stores
+// a displacement that maps its keys to distinct slots, so every key owns exactly one of n slots and a lookup
+// takes exactly one probe. Singleton buckets store their slot directly.
+// FrozenMap is created with FastMap.Freeze, read with DecodeBinary, or memory mapped with OpenFrozen.
+type FrozenMap[T any] struct {
+	seed       uint64
+	disp       []uint32 // Displacement per bucket, or frozenDirect|slot for singleton buckets
+	keys       []int64
+	data       []T
+	hasFreeKey bool
+	freeVal    T
+	mapped     []byte // Memory mapped file backing the slots
+}
+
+// bucket returns the bucket of the key hash
+func (f *FrozenMap[T]) bucket(h uint64) uint64 {
+	b, _ := bits.Mul64(h, uint64(len(f.disp)))
+	return b
+}
+
+// slot returns the slot of the key hash
+func (f *FrozenMap[T]) slot(h uint64) uint64 {
+	d := f.disp[f.bucket(h)]
+	if d&frozenDirect != 0 {
+		return uint64(d &^ frozenDirect)
+	}
+	return displace(h, d, len(f.keys))
+}
+
+// displace maps the key hash with the displacement to one of n slots
+func displace(h uint64, d uint32, n int) uint64 {
+	s, _ := bits.Mul64(mix64(h^uint64(d)*0x9E3779B97F4A7C15), uint64(n))
+	return s
+}
+
+// Get retrieves the value associated with the given key.
+// It returns the value and a boolean indicating whether the key was found.
+func (f *FrozenMap[T]) Get(key int64) (T, bool) {
+	if key == FREE_KEY {
+		if f.hasFreeKey {
+			return f.freeVal, true
+		}
+		var zero T
+		return zero, false
+	}
+	if len(f.keys) > 0 {
+		if ptr := f.slot(seededMix(key, f.seed)); f.keys[ptr] == key {
+			return f.data[ptr], true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// All returns an iterator over all map entries, starting with FREE_KEY.
+func (f *FrozenMap[T]) All() iter.Seq2[int64, T] {
+	return func(yield func(int64, T) bool) {
+		if f.hasFreeKey && !yield(FREE_KEY, f.freeVal) {
+			return
+		}
+		for i, key := range f.keys {
+			if !yield(key, f.data[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Size returns the number of elements in the map.
+func (f *FrozenMap[T]) Size() int {
+	if f == nil {
+		return 0
+	}
+	if f.hasFreeKey {
+		return len(f.keys) + 1
+	}
+	return len(f.keys)
+}
+
+// Close unmaps the file of the map opened with OpenFrozen; the map must not be used afterwards.
+func (f *FrozenMap[T]) Close() error {
+	if f.mapped == nil {
+		return nil
+	}
+	data := f.mapped
+	*f = FrozenMap[T]{}
+	return unmapFile(data)
+}
+
+// validate checks that decoded displacements address existing slots
+func (f *FrozenMap[T]) validate() error {
+	if len(f.disp) == 0 {
+		return fmt.Errorf("corrupted FrozenMap: no buckets")
+	}
+	for _, d := range f.disp {
+		if d&frozenDirect != 0 && int(d&^frozenDirect) >= len(f.keys) {
+			return fmt.Errorf("corrupted FrozenMap: invalid slot: %v", d&^frozenDirect)
+		}
+	}
+	return nil
+}
+
+// Freeze returns an immutable copy of the map backed by a minimal perfect hash.
+func (m *FastMap[T]) Freeze() *FrozenMap[T] {
+	keys := make([]int64, 0, m.size)
+	values := make([]T, 0, m.size)
+	for i, key := range m.keys {
+		if key != FREE_KEY {
+			keys = append(keys, key)
+			values = append(values, m.data[i])
+		}
+	}
+	f := &FrozenMap[T]{hasFreeKey: m.hasFreeKey, freeVal: m.freeVal}
+	f.build(keys, values)
+	return f
+}
+
+// build assigns slots to the distinct non FREE_KEY keys, trying seeds until every bucket finds a displacement
+func (f *FrozenMap[T]) build(keys []int64, values []T) {
+	n := len(keys)
+	f.keys = make([]int64, n)
+	f.data = make([]T, n)
+	f.disp = make([]uint32, max(1, (n+frozenBucketSize-1)/frozenBucketSize))
+	hashes := make([]uint64, n)
+	slots := make([]uint64, n)
+	taken := make([]bool, n)
+	for attempt := uint64(0); ; attempt++ {
+		f.seed = seededMix(int64(attempt), frozenSeed)
+		if f.place(keys, hashes, slots, taken) {
+			break
+		}
+		if attempt > 64 {
+			panic(fmt.Sprintf("unable to build perfect hash for %v keys", n))
+		}
+		clear(taken)
+	}
+	for i, s := range slots {
+		f.keys[s] = keys[i]
+		f.data[s] = values[i]
+	}
+}
+
+// place assigns every key to a distinct slot with the current seed and returns false if some bucket found no
+// displacement. Buckets are placed from the largest, while most slots are free; singleton buckets take
+// the remaining slots directly.
+func (f *FrozenMap[T]) place(keys []int64, hashes, slots []uint64, taken []bool) bool {
+	nb := len(f.disp)
+	start := make([]int, nb+1) // Keys of bucket b are members[start[b]:start[b+1]]
+	for i, key := range keys {
+		hashes[i] = seededMix(key, f.seed)
+		start[f.bucket(hashes[i])+1]++
+	}
+	largest := 0
+	for b := 0; b < nb; b++ {
+		largest = max(largest, start[b+1])
+		start[b+1] += start[b]
+	}
+	members := make([]int, len(keys))
+	fill := append([]int(nil), start[:nb]...)
+	for i := range keys {
+		b := f.bucket(hashes[i])
+		members[fill[b]] = i
+		fill[b]++
+	}
+	bySize := make([][]int, largest+1) // Buckets grouped by number of keys
+	for b := 0; b < nb; b++ {
+		size := start[b+1] - start[b]
+		bySize[size] = append(bySize[size], b)
+	}
+	for size := largest; size > 1; size-- {
+		for _, b := range bySize[size] {
+			if !f.displaceBucket(b, members[start[b]:start[b+1]], hashes, slots, taken) {
+				return false
+			}
+		}
+	}
+	if largest == 0 {
+		return true
+	}
+	free := 0
+	for _, b := range bySize[1] {
+		for taken[free] {
+			free++
+		}
+		taken[free] = true
+		slots[members[start[b]]] = uint64(free)
+		f.disp[b] = frozenDirect | uint32(free)
+	}
+	return true
+}
+
+// displaceBucket finds the first displacement mapping keys of the bucket to distinct free slots
+func (f *FrozenMap[T]) displaceBucket(b int, members []int, hashes, slots []uint64, taken []bool) bool {
+	n := len(taken)
+	for d := uint32(0); d < frozenMaxDisp; d++ {
+		placed := 0
+		for _, i := range members {
+			s := displace(hashes[i], d, n)
+			if taken[s] {
+				break
+			}
+			taken[s] = true
+			slots[i] = s
+			placed++
+		}
+		if placed == len(members) {
+			f.disp[b] = d
+			return true
+		}
+		for _, i := range members[:placed] {
+			taken[slots[i]] = false
+		}
+	}
+	return false
+}
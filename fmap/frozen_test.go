@@ -0,0 +1,119 @@
+package fmap
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFastMap_Freeze(t *testing.T) {
+	for _, size := range []int{0, 1, 3, 1000, 100000} {
+		m := NewFastMap[int64](4, 0.75)
+		for i := int64(1); i <= int64(size); i++ {
+			m.Put(i*7919, i)
+		}
+		frozen := m.Freeze()
+		assert.Equal(t, size, frozen.Size())
+		assert.Equal(t, size, len(frozen.keys), "one slot per key")
+		for i := int64(1); i <= int64(size); i++ {
+			val, ok := frozen.Get(i * 7919)
+			assert.True(t, ok)
+			assert.Equal(t, i, val)
+		}
+		_, ok := frozen.Get(1)
+		assert.False(t, ok)
+		_, ok = frozen.Get(FREE_KEY)
+		assert.False(t, ok)
+	}
+}
+
+func TestFrozenMap_WriteTo(t *testing.T) {
+	m := NewFastMap[string](4, 0.75)
+	m.Put(0, "zero")
+	for i := int64(1); i < 500; i++ {
+		m.Put(-i, "v")
+	}
+	frozen := m.Freeze()
+	buffer := new(bytes.Buffer)
+	_, err := frozen.WriteTo(buffer)
+	assert.Nil(t, err)
+	decoded := &FrozenMap[string]{}
+	_, err = decoded.ReadFrom(bytes.NewReader(buffer.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, frozen.Size(), decoded.Size())
+	for key, value := range frozen.All() {
+		actual, ok := decoded.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, value, actual)
+	}
+}
+
+// TestFrozenMap_WriteTo_Failed verifies that a failed encoding does not leak partial output into later encodings.
+func TestFrozenMap_WriteTo_Failed(t *testing.T) {
+	unsupported := NewFastMap[[]int](4, 0.75)
+	unsupported.Put(1, []int{1})
+	m := NewFastMap[int](4, 0.75)
+	m.Put(2, 2)
+	for i := 0; i < 3; i++ {
+		_, err := unsupported.Freeze().WriteTo(new(bytes.Buffer))
+		assert.NotNil(t, err)
+		buffer := new(bytes.Buffer)
+		_, err = m.Freeze().WriteTo(buffer)
+		assert.Nil(t, err)
+		decoded := &FrozenMap[int]{}
+		_, err = decoded.ReadFrom(bytes.NewReader(buffer.Bytes()))
+		if assert.Nil(t, err) {
+			val, ok := decoded.Get(2)
+			assert.True(t, ok)
+			assert.Equal(t, 2, val)
+		}
+	}
+}
+
+func TestOpenFrozen(t *testing.T) {
+	m := NewFastMap[codecPoint](4, 0.75)
+	m.Put(0, codecPoint{ID: -1})
+	for i := int64(1); i < 1000; i++ {
+		m.Put(i*3, codecPoint{X: float32(i), ID: i})
+	}
+	path := filepath.Join(t.TempDir(), "map.frozen")
+	file, err := os.Create(path)
+	assert.Nil(t, err)
+	_, err = m.Freeze().WriteMapped(file)
+	assert.Nil(t, err)
+	assert.Nil(t, file.Close())
+
+	frozen, err := OpenFrozen[codecPoint](path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 1000, frozen.Size())
+	for i := int64(1); i < 1000; i++ {
+		val, ok := frozen.Get(i * 3)
+		assert.True(t, ok)
+		assert.Equal(t, codecPoint{X: float32(i), ID: i}, val)
+	}
+	val, ok := frozen.Get(0)
+	assert.True(t, ok)
+	assert.Equal(t, int64(-1), val.ID)
+	assert.Nil(t, frozen.Close())
+
+	_, err = OpenFrozen[int32](path)
+	assert.NotNil(t, err)
+	_, err = OpenMapped[codecPoint](path)
+	assert.NotNil(t, err)
+}
+
+func BenchmarkFrozenMap_Get(b *testing.B) {
+	m := NewFastMap[int](1<<16, 0.75)
+	for i := int64(1); i <= 1<<16; i++ {
+		m.Put(i*7, int(i))
+	}
+	frozen := m.Freeze()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		frozen.Get(int64(i&(1<<16-1)) * 7)
+	}
+}
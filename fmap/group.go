@@ -0,0 +1,154 @@
+package fmap
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sync/atomic"
+)
+
+const (
+	groupSize         = 8                  // Number of control bytes probed at once
+	ctrlEmpty   uint8 = 0x80               // Control byte of an empty slot
+	ctrlDeleted uint8 = 0xFE               // Control byte of a deleted slot
+	ctrlLsbs          = 0x0101010101010101 // Lowest bit of every control byte in a group
+	ctrlMsbs          = 0x8080808080808080 // Highest bit of every control byte in a group
+)
+
+// newCtrl returns control bytes for the given computeCapacity with all slots empty
+func newCtrl(capacity int) []uint8 {
+	ctrl := make([]uint8, capacity)
+	for i := range ctrl {
+		ctrl[i] = ctrlEmpty
+	}
+	return ctrl
+}
+
+// matchTag returns a bit mask with the highest bit set for every control byte equal to the tag.
+// It may report false positives next to a true match, which are ruled out by comparing keys.
+func matchTag(group uint64, tag uint64) uint64 {
+	x := group ^ (ctrlLsbs * tag)
+	return (x - ctrlLsbs) &^ x & ctrlMsbs
+}
+
+// matchEmpty returns a bit mask with the highest bit set for every empty control byte
+func matchEmpty(group uint64) uint64 {
+	return group &^ (group << 6) & ctrlMsbs
+}
+
+// matchEmptyOrDeleted returns a bit mask with the highest bit set for every empty or deleted control byte
+func matchEmptyOrDeleted(group uint64) uint64 {
+	return group &^ (group << 7) & ctrlMsbs
+}
+
+// group loads control bytes of the group at index g
+func (m *FastMap[T]) group(g uint64) uint64 {
+	return binary.LittleEndian.Uint64(m.ctrl[g*groupSize:])
+}
+
+// findGroup returns the slot holding the given non FREE_KEY key, or -1 if the key is absent.
+// Groups are visited in triangular order starting from the home group; the search stops at the first group
+// with an empty slot.
+func (m *FastMap[T]) findGroup(key int64) int64 {
+	h := uint64(m.hash(key))
+	tag := h & 0x7F
+	groupMask := uint64(m.mask) / groupSize
+	g := (h >> 7) & groupMask
+	for step := uint64(1); ; step++ {
+		group := m.group(g)
+		for match := matchTag(group, tag); match != 0; match &= match - 1 {
+			ptr := int64(g*groupSize) + int64(bits.TrailingZeros64(match)>>3)
+			if m.keys[ptr] == key {
+				return ptr
+			}
+		}
+		if matchEmpty(group) != 0 {
+			return -1
+		}
+		g = (g + step) & groupMask
+	}
+}
+
+// getGroup retrieves the value associated with the given non FREE_KEY key.
+func (m *FastMap[T]) getGroup(key int64) (T, bool) {
+	if ptr := m.findGroup(key); ptr >= 0 {
+		return m.data[ptr], true
+	}
+	var zero T
+	return zero, false
+}
+
+// putGroup adds or updates the given non FREE_KEY key.
+func (m *FastMap[T]) putGroup(key int64, val T) {
+	ptr, found := m.locateGroup(key)
+	if found {
+		m.data[ptr] = val
+		return
+	}
+	m.insertGroup(ptr, key, val)
+}
+
+// locateGroup returns the slot holding the key, or the first empty or deleted slot on its probe sequence if absent.
+func (m *FastMap[T]) locateGroup(key int64) (int64, bool) {
+	h := uint64(m.hash(key))
+	tag := h & 0x7F
+	groupMask := uint64(m.mask) / groupSize
+	g := (h >> 7) & groupMask
+	ptr := int64(-1)
+	for step := uint64(1); ; step++ {
+		group := m.group(g)
+		for match := matchTag(group, tag); match != 0; match &= match - 1 {
+			i := int64(g*groupSize) + int64(bits.TrailingZeros64(match)>>3)
+			if m.keys[i] == key {
+				return i, true
+			}
+		}
+		if ptr < 0 {
+			if match := matchEmptyOrDeleted(group); match != 0 {
+				ptr = int64(g*groupSize) + int64(bits.TrailingZeros64(match)>>3)
+			}
+		}
+		if matchEmpty(group) != 0 {
+			return ptr, false
+		}
+		g = (g + step) & groupMask
+	}
+}
+
+// insertGroup places the key at the slot returned by locateGroup.
+func (m *FastMap[T]) insertGroup(ptr int64, key int64, val T) {
+	atomic.AddUint32(&m.scn, 1) //added new key
+	if m.ctrl[ptr] == ctrlDeleted {
+		m.tombstones--
+	}
+	m.ctrl[ptr] = uint8(m.hash(key) & 0x7F)
+	m.keys[ptr] = key
+	m.data[ptr] = val
+	m.size++
+	if m.size+m.tombstones >= m.threshold {
+		m.rehashGroup()
+	}
+}
+
+// rehashGroup grows the map, or rehashes it in place when most of the used slots are tombstones.
+func (m *FastMap[T]) rehashGroup() {
+	if m.size*2 < m.threshold {
+		m.resize(len(m.keys))
+		return
+	}
+	m.rehash()
+}
+
+// removeGroup empties the occupied slot at ptr.
+// The slot becomes empty when its group already has an empty slot, since no probe sequence continues past
+// such a group; otherwise it is marked deleted to keep probe sequences intact.
+func (m *FastMap[T]) removeGroup(ptr int64) {
+	var zero T
+	if matchEmpty(m.group(uint64(ptr)/groupSize)) != 0 {
+		m.ctrl[ptr] = ctrlEmpty
+	} else {
+		m.ctrl[ptr] = ctrlDeleted
+		m.tombstones++
+	}
+	m.keys[ptr] = FREE_KEY
+	m.data[ptr] = zero
+}
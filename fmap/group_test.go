@@ -0,0 +1,59 @@
+package fmap
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// TestFastMap_GroupProbingTombstones tests that churn with tombstones triggers in-place rehashing instead of growth.
+func TestFastMap_GroupProbingTombstones(t *testing.T) {
+	m := NewFastMap[int](64, 0.75, WithGroupProbing())
+	capacity := m.Cap()
+	for i := int64(1); i <= 100000; i++ {
+		m.Put(i, int(i))
+		if i > 32 {
+			m.Delete(i - 32)
+		}
+	}
+	assert.Equal(t, 32, m.Size())
+	assert.Equal(t, capacity, m.Cap())
+	for i := int64(100000 - 31); i <= 100000; i++ {
+		val, ok := m.Get(i)
+		assert.True(t, ok)
+		assert.Equal(t, int(i), val)
+	}
+}
+
+// TestFastMap_GroupProbingCodec tests binary encoding of a map using group probing.
+func TestFastMap_GroupProbingCodec(t *testing.T) {
+	m := NewFastMap[int64](4, 0.75, WithGroupProbing())
+	for i := int64(0); i < 100; i++ {
+		m.Put(i<<16, i)
+	}
+	m.Delete(5 << 16)
+	buffer := new(bytes.Buffer)
+	_, err := m.WriteTo(buffer)
+	assert.Nil(t, err)
+	decoded := &FastMap[int64]{}
+	_, err = decoded.ReadFrom(buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, m.ctrl, decoded.ctrl)
+	for i := int64(0); i < 100; i++ {
+		val, ok := decoded.Get(i << 16)
+		assert.Equal(t, i != 5, ok)
+		if ok {
+			assert.Equal(t, i, val)
+		}
+	}
+
+	mapped, err := OpenMapped[int64](writeMapped(t, m))
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer mapped.Close()
+	for i := int64(0); i < 100; i++ {
+		_, ok := mapped.Get(i << 16)
+		assert.Equal(t, i != 5, ok)
+	}
+}
@@ -0,0 +1,135 @@
+package fmap
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strings"
+	"sync"
+)
+
+// hasherKind identifies a hash function in serialized maps
+type hasherKind uint8
+
+const (
+	phiHasher        hasherKind = iota // Default phiMix
+	seededHasher                       // Built-in seeded mixer
+	registeredHasher                   // Custom hasher registered by name
+)
+
+// hasher represents an int64 key hash function other than phiMix
+type hasher struct {
+	kind hasherKind
+	name string // Name of registered hasher
+	seed uint64 // Seed of seeded hasher
+	fn   func(int64) uint64
+}
+
+// id returns a 64-bit identifier of the hasher: the seed for seeded hasher, or the name hash for registered hasher
+func (h *hasher) id() uint64 {
+	if h.kind == registeredHasher {
+		return hashString(h.name)
+	}
+	return h.seed
+}
+
+var hasherRegistry = struct {
+	sync.RWMutex
+	fns map[string]func(int64) uint64
+}{fns: map[string]func(int64) uint64{}}
+
+// builtinHasherPrefix prefixes names of hashers registered by the package, which RegisterHasher refuses to replace
+const builtinHasherPrefix = "fmap."
+
+// RegisterHasher registers a custom key hash function under the name.
+// Maps built with WithHasher record only the hasher name when serialized, so a process loading such a map
+// has to register the same function under the same name first. Names starting with "fmap." are reserved.
+func RegisterHasher(name string, fn func(int64) uint64) {
+	if strings.HasPrefix(name, builtinHasherPrefix) {
+		panic(fmt.Sprintf("hasher name %q is reserved", name))
+	}
+	registerHasher(name, fn)
+}
+
+// registerHasher adds the hasher to the registry
+func registerHasher(name string, fn func(int64) uint64) {
+	if name == "" || fn == nil {
+		panic("hasher name and function are required")
+	}
+	hasherRegistry.Lock()
+	hasherRegistry.fns[name] = fn
+	hasherRegistry.Unlock()
+}
+
+// lookupHasher returns the hasher for serialized kind and name or id
+func lookupHasher(kind hasherKind, name string, id uint64) (*hasher, error) {
+	switch kind {
+	case phiHasher:
+		return nil, nil
+	case seededHasher:
+		return newSeededHasher(id), nil
+	case registeredHasher:
+		hasherRegistry.RLock()
+		defer hasherRegistry.RUnlock()
+		if name == "" {
+			for candidate := range hasherRegistry.fns {
+				if hashString(candidate) == id {
+					name = candidate
+					break
+				}
+			}
+		}
+		if fn, ok := hasherRegistry.fns[name]; ok {
+			return &hasher{kind: registeredHasher, name: name, fn: fn}, nil
+		}
+		return nil, fmt.Errorf("unknown hasher: %q, use RegisterHasher before loading the map", name)
+	}
+	return nil, fmt.Errorf("unsupported hasher kind: %v", kind)
+}
+
+// seededMix is a keyed multiply-fold mixer; without the seed an attacker cannot construct colliding keys.
+func seededMix(key int64, seed uint64) uint64 {
+	hi, lo := bits.Mul64(uint64(key)^seed^0xA0761D6478BD642F, seed^0xE7037ED1A0B428DB)
+	hi, lo = bits.Mul64(hi^lo^0x8EBC6AF09C88C6E3, seed^0x589965CC75374CC3)
+	return hi ^ lo
+}
+
+// newSeededHasher returns the seeded hasher
+func newSeededHasher(seed uint64) *hasher {
+	return &hasher{kind: seededHasher, seed: seed, fn: func(key int64) uint64 {
+		return seededMix(key, seed)
+	}}
+}
+
+// randomSeed returns a cryptographically random seed
+func randomSeed() uint64 {
+	var seed [8]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		panic(fmt.Sprintf("unable to generate hasher seed: %v", err))
+	}
+	return binary.LittleEndian.Uint64(seed[:])
+}
+
+// useHasher sets the map hasher, nil restores phiMix
+func (m *FastMap[T]) useHasher(h *hasher) {
+	m.hasher = h
+	m.hashFn = nil
+	if h != nil {
+		m.hashFn = h.fn
+	}
+}
+
+// hash returns the hash of the key using the map hasher
+func (m *FastMap[T]) hash(key int64) int64 {
+	return hashKey(m.hashFn, key)
+}
+
+// hashKey returns the hash of the key using fn, or phiMix when fn is nil.
+// It is kept non-generic, so that it is cheap enough to be inlined into probing loops.
+func hashKey(fn func(int64) uint64, key int64) int64 {
+	if fn == nil {
+		return phiMix(key)
+	}
+	return int64(fn(key))
+}
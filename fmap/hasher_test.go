@@ -0,0 +1,91 @@
+package fmap
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFastMap_Hasher(t *testing.T) {
+	identity := func(key int64) uint64 { return uint64(key) }
+	RegisterHasher("test-identity", identity)
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "Seed", opts: []Option{WithSeed(42)}},
+		{name: "RandomSeed", opts: []Option{WithRandomSeed()}},
+		{name: "Custom", opts: []Option{WithHasher("test-identity", identity)}},
+		{name: "SeedRobinHood", opts: []Option{WithSeed(7), WithRobinHood()}},
+		{name: "SeedGroup", opts: []Option{WithSeed(7), WithGroupProbing()}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewFastMap[int64](4, 0.75, tc.opts...)
+			for i := int64(0); i < 2000; i++ {
+				m.Put(i<<16, i)
+			}
+			for i := int64(0); i < 2000; i += 2 {
+				_, ok := m.Delete(i << 16)
+				assert.True(t, ok)
+			}
+			buffer := new(bytes.Buffer)
+			_, err := m.WriteTo(buffer)
+			assert.Nil(t, err)
+			decoded := &FastMap[int64]{}
+			_, err = decoded.ReadFrom(buffer)
+			if !assert.Nil(t, err) {
+				return
+			}
+			mapped, err := OpenMapped[int64](writeMapped(t, m))
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer mapped.Close()
+			for i := int64(0); i < 2000; i++ {
+				for _, get := range []func(int64) (int64, bool){m.Get, decoded.Get, mapped.Get} {
+					val, ok := get(i << 16)
+					assert.Equal(t, i%2 == 1, ok)
+					if ok {
+						assert.Equal(t, i, val)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFastMap_HasherUnknown(t *testing.T) {
+	m := NewFastMap[int](4, 0.75, WithHasher("test-unknown", func(key int64) uint64 { return uint64(key) }))
+	m.Put(1, 1)
+	buffer := new(bytes.Buffer)
+	_, err := m.WriteTo(buffer)
+	assert.Nil(t, err)
+	_, err = (&FastMap[int]{}).ReadFrom(buffer)
+	assert.NotNil(t, err, "WithHasher does not register the hasher")
+}
+
+func TestRegisterHasher_Reserved(t *testing.T) {
+	assert.Panics(t, func() { RegisterHasher(pairHasherName, func(key int64) uint64 { return 0 }) })
+	m := NewPairMap[int](4, 0.75)
+	m.Put(1, 2, 3)
+	buffer := new(bytes.Buffer)
+	_, err := m.index.(*FastMap[int32]).WriteTo(buffer)
+	assert.Nil(t, err)
+	decoded := &FastMap[int32]{}
+	_, err = decoded.ReadFrom(buffer)
+	if assert.Nil(t, err) {
+		_, ok := decoded.Get(packPair(1, 2))
+		assert.True(t, ok)
+	}
+}
+
+// TestSeededMixSpread verifies that the seeded mixer spreads keys that cluster with phiMix.
+func TestSeededMixSpread(t *testing.T) {
+	const slots = 1 << 10
+	used := map[uint64]bool{}
+	for i := int64(0); i < slots; i++ {
+		used[seededMix(i<<32, 1)&(slots-1)] = true
+	}
+	assert.Greater(t, len(used), slots/2)
+}
@@ -0,0 +1,123 @@
+package fmap
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrConcurrentModification is reported when the map is structurally modified while being iterated
+var ErrConcurrentModification = errors.New("fmap: map modified during iteration")
+
+// All returns an iterator over all map entries, starting with FREE_KEY.
+// Values may be updated while iterating, but unlike ranging over a Go map, it panics with ErrConcurrentModification
+// when any key is added or removed, including the key just yielded, since rehashing and deletion move entries
+// between slots. Collect keys first to delete them.
+func (m *FastMap[T]) All() iter.Seq2[int64, T] {
+	return func(yield func(int64, T) bool) {
+		scn := m.scn
+		if m.hasFreeKey && !yield(FREE_KEY, m.freeVal) {
+			return
+		}
+		for i := 0; i < len(m.keys); i++ {
+			if m.scn != scn {
+				panic(ErrConcurrentModification)
+			}
+			if key := m.keys[i]; key != FREE_KEY && !yield(key, m.data[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over all map keys with the same guarantees as All: it panics with
+// ErrConcurrentModification when any key, including the key just yielded, is added or removed while iterating.
+func (m *FastMap[T]) Keys() iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for key := range m.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over all map values with the same guarantees as All: it panics with
+// ErrConcurrentModification when any key is added or removed while iterating.
+func (m *FastMap[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, value := range m.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// CheckedIterator returns a function iterating over all map entries; the third return value is false once exhausted.
+// Once a key is added or removed, the function stops and returns ErrConcurrentModification.
+func (m *FastMap[T]) CheckedIterator() func() (int64, T, bool, error) {
+	cursor := Cursor{}
+	return func() (int64, T, bool, error) {
+		var key int64
+		var value T
+		found := false
+		next, err := m.Scan(cursor, 1, func(k int64, v T) {
+			key, value, found = k, v, true
+		})
+		if err != nil {
+			return 0, value, false, err
+		}
+		cursor = next
+		return key, value, found, nil
+	}
+}
+
+// Cursor represents the position of a paginated Scan; the zero value starts a new scan.
+type Cursor struct {
+	pos int    // Next position, 0 stands for FREE_KEY, i+1 for slot i
+	scn uint32 // Map SCN when the scan started
+}
+
+// Done returns true if the scan visited all slots
+func (c Cursor) Done() bool {
+	return c.pos < 0
+}
+
+// Scan calls fn with up to limit entries following the cursor and returns the cursor to resume the scan from.
+// Scanning can be interleaved with value updates, but it returns ErrConcurrentModification when a key was added
+// or removed since the scan started, in which case the scan has to restart from the zero Cursor.
+// limit has to be positive, so that every call advances the cursor.
+func (m *FastMap[T]) Scan(cursor Cursor, limit int, fn func(key int64, value T)) (Cursor, error) {
+	if limit < 1 {
+		return cursor, fmt.Errorf("invalid Scan limit: %v", limit)
+	}
+	if cursor.Done() {
+		return cursor, nil
+	}
+	if cursor.pos == 0 {
+		cursor.scn = m.scn
+		cursor.pos = 1
+		if m.hasFreeKey {
+			fn(FREE_KEY, m.freeVal)
+			limit--
+		}
+	} else if cursor.scn != m.scn {
+		return cursor, ErrConcurrentModification
+	}
+	i := cursor.pos - 1
+	for ; i < len(m.keys) && limit > 0; i++ {
+		if key := m.keys[i]; key != FREE_KEY {
+			fn(key, m.data[i])
+			limit--
+		}
+	}
+	for i < len(m.keys) && m.keys[i] == FREE_KEY {
+		i++
+	}
+	cursor.pos = i + 1
+	if i == len(m.keys) {
+		cursor.pos = -1
+	}
+	return cursor, nil
+}
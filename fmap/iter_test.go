@@ -0,0 +1,112 @@
+package fmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFastMap_All(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[int](4, 0.75, mode.opts...)
+			for i := int64(0); i < 20; i++ {
+				m.Put(i, int(i)*10)
+			}
+			seen := map[int64]int{}
+			for key, value := range m.All() {
+				seen[key] = value
+				m.Put(key, value+1) // value updates are allowed
+			}
+			assert.Equal(t, 20, len(seen))
+			assert.Equal(t, 190, seen[19])
+			var keys []int64
+			for key := range m.Keys() {
+				keys = append(keys, key)
+			}
+			assert.Equal(t, 20, len(keys))
+			sum := 0
+			for value := range m.Values() {
+				sum += value
+			}
+			assert.Equal(t, 1920, sum)
+			for range m.All() {
+				break
+			}
+			assert.PanicsWithValue(t, ErrConcurrentModification, func() {
+				for key := range m.Keys() {
+					m.Delete(key)
+				}
+			})
+		})
+	}
+}
+
+func TestFastMap_CheckedIterator(t *testing.T) {
+	m := NewFastMap[int](4, 0.75)
+	for i := int64(0); i < 10; i++ {
+		m.Put(i, int(i))
+	}
+	next := m.CheckedIterator()
+	count := 0
+	for _, _, ok, err := next(); ok; _, _, ok, err = next() {
+		assert.Nil(t, err)
+		count++
+	}
+	assert.Equal(t, 10, count)
+
+	next = m.CheckedIterator()
+	_, _, ok, err := next()
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	m.Put(100, 100)
+	_, _, ok, err = next()
+	assert.False(t, ok)
+	assert.Equal(t, ErrConcurrentModification, err)
+}
+
+func TestFastMap_Scan(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[int](4, 0.75, mode.opts...)
+			for i := int64(0); i < 25; i++ {
+				m.Put(i, int(i))
+			}
+			seen := map[int64]bool{}
+			cursor := Cursor{}
+			pages := 0
+			for !cursor.Done() {
+				var err error
+				page := 0
+				cursor, err = m.Scan(cursor, 10, func(key int64, value int) {
+					seen[key] = true
+					page++
+				})
+				assert.Nil(t, err)
+				assert.True(t, page <= 10)
+				pages++
+				m.Put(1, -1)
+			}
+			assert.Equal(t, 25, len(seen))
+			assert.Equal(t, 3, pages)
+
+			cursor, _ = m.Scan(Cursor{}, 10, func(key int64, value int) {})
+			m.Delete(1)
+			_, err := m.Scan(cursor, 10, func(key int64, value int) {})
+			assert.Equal(t, ErrConcurrentModification, err)
+
+			for _, limit := range []int{0, -1} {
+				next, err := m.Scan(Cursor{}, limit, func(key int64, value int) {
+					t.Fatalf("unexpected entry %d with limit %d", key, limit)
+				})
+				assert.NotNil(t, err)
+				assert.Equal(t, Cursor{}, next)
+			}
+			count := 0
+			for cursor = (Cursor{}); !cursor.Done(); {
+				cursor, err = m.Scan(cursor, 1, func(key int64, value int) { count++ })
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, m.Size(), count)
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package fmap
+
+import (
+	"math"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Key is a constraint for KeyMap key types: integer-like types and fixed-size byte arrays such as UUIDs.
+// All of them are free of padding and pointers, so their raw bytes can be hashed directly.
+type Key interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~[4]byte | ~[8]byte | ~[16]byte
+}
+
+// UUID represents a 16 byte key, i.e. UUID
+type UUID [16]byte
+
+// mix64 is a 64-bit finalizer used to combine multi-word keys.
+func mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xFF51AFD7ED558CCD
+	x ^= x >> 33
+	x *= 0xC4CEB9FE1A85EC53
+	x ^= x >> 33
+	return x
+}
+
+// MixKey returns a hash for the given key.
+// Keys up to 8 bytes are zero-extended to int64 and scrambled with phiMix, so int64 keys hash exactly as in FastMap;
+// 16 byte keys combine both words with mix64.
+func MixKey[K Key](key K) uint64 {
+	p := unsafe.Pointer(&key)
+	switch unsafe.Sizeof(key) {
+	case 1:
+		return uint64(phiMix(int64(*(*uint8)(p))))
+	case 2:
+		return uint64(phiMix(int64(*(*uint16)(p))))
+	case 4:
+		return uint64(phiMix(int64(*(*uint32)(p))))
+	case 8:
+		return uint64(phiMix(*(*int64)(p)))
+	default:
+		lo := *(*uint64)(p)
+		hi := *(*uint64)(unsafe.Add(p, 8))
+		return mix64(lo ^ mix64(hi))
+	}
+}
+
+// KeyMap is a high-performance hash map for integer-like and fixed-size keys.
+// It shares FastMap's open addressing layout with linear probing; the zero key is used to denote an empty slot
+// and is stored separately, the same way FastMap handles FREE_KEY.
+// This implementation is not safe for concurrent use.
+type KeyMap[K Key, T any] struct {
+	keys       []K     // Array of keys
+	data       []T     // Array of values corresponding to keys
+	fillFactor float64 // Fill factor for resizing the map
+	threshold  int     // Resize threshold based on computeCapacity and fill factor
+	size       int     // Number of elements in the map
+	cap        uint32
+	mask       uint64 // Mask for calculating indices during probing
+	hasFreeKey bool   // Indicates if the map contains the zero key
+	freeVal    T      // Value associated with the zero key
+	scn        uint32
+}
+
+// Get retrieves the value associated with the given key.
+// It returns the value and a boolean indicating whether the key was found.
+func (m *KeyMap[K, T]) Get(key K) (T, bool) {
+	var zero T
+	var freeKey K
+	if key == freeKey {
+		if m.hasFreeKey {
+			return m.freeVal, true
+		}
+		return zero, false
+	}
+	keys := m.keys
+	ptr := MixKey(key) & m.mask
+	for {
+		k := keys[ptr]
+		if k == freeKey {
+			return zero, false
+		}
+		if k == key {
+			return m.data[ptr], true
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+}
+
+// GetPointer retrieves the value pointer associated with the given key.
+// It returns the value and a boolean indicating whether the key was found.
+func (m *KeyMap[K, T]) GetPointer(key K) (*T, bool) {
+	var freeKey K
+	if key == freeKey {
+		if m.hasFreeKey {
+			return &m.freeVal, true
+		}
+		return nil, false
+	}
+	ptr := MixKey(key) & m.mask
+	for {
+		k := m.keys[ptr]
+		if k == freeKey {
+			return nil, false
+		}
+		if k == key {
+			return &m.data[ptr], true
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+}
+
+// Put adds or updates the key with the value val.
+func (m *KeyMap[K, T]) Put(key K, val T) {
+	var freeKey K
+	if key == freeKey {
+		if !m.hasFreeKey {
+			m.size++
+			atomic.AddUint32(&m.scn, 1) //added new key
+		}
+		m.hasFreeKey = true
+		m.freeVal = val
+		return
+	}
+	ptr := MixKey(key) & m.mask
+	for {
+		k := m.keys[ptr]
+		if k == freeKey {
+			atomic.AddUint32(&m.scn, 1) //added new key
+			m.data[ptr] = val
+			m.keys[ptr] = key
+			m.size++
+			if m.size >= m.threshold {
+				m.rehash()
+			}
+			return
+		}
+		if k == key {
+			m.data[ptr] = val
+			return
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+}
+
+// GetOrInsert returns the pointer to the value associated with the key, inserting the value returned by fn
+// when the key is absent, with a single probe.
+// It returns the pointer, a boolean indicating whether the key was found, and a boolean indicating whether
+// the insertion moved existing entries, which invalidates pointers returned earlier by GetPointer.
+// fn must not modify the map.
+func (m *KeyMap[K, T]) GetOrInsert(key K, fn func() T) (*T, bool, bool) {
+	var freeKey K
+	if key == freeKey {
+		if m.hasFreeKey {
+			return &m.freeVal, true, false
+		}
+		m.Put(key, fn())
+		return &m.freeVal, false, false
+	}
+	ptr := MixKey(key) & m.mask
+	for {
+		k := m.keys[ptr]
+		if k == key {
+			return &m.data[ptr], true, false
+		}
+		if k == freeKey {
+			val := fn()
+			atomic.AddUint32(&m.scn, 1) //added new key
+			m.data[ptr] = val
+			m.keys[ptr] = key
+			m.size++
+			if m.size < m.threshold {
+				return &m.data[ptr], false, false
+			}
+			m.rehash()
+			valPtr, _ := m.GetPointer(key)
+			return valPtr, false, true
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+}
+
+// Delete removes the key from the map.
+// It returns the removed value and a boolean indicating whether the key was found.
+func (m *KeyMap[K, T]) Delete(key K) (T, bool) {
+	var zero T
+	var freeKey K
+	if key == freeKey {
+		if !m.hasFreeKey {
+			return zero, false
+		}
+		val := m.freeVal
+		m.hasFreeKey = false
+		m.freeVal = zero
+		m.size--
+		atomic.AddUint32(&m.scn, 1) //removed key
+		return val, true
+	}
+	ptr := MixKey(key) & m.mask
+	for {
+		k := m.keys[ptr]
+		if k == freeKey {
+			return zero, false
+		}
+		if k == key {
+			break
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+	val := m.data[ptr]
+	m.shiftBackward(ptr)
+	m.size--
+	atomic.AddUint32(&m.scn, 1) //removed key
+	return val, true
+}
+
+// shiftBackward empties the slot at ptr and moves subsequent entries of the probe chain back into the gap.
+func (m *KeyMap[K, T]) shiftBackward(ptr uint64) {
+	var zero T
+	var freeKey K
+	next := ptr
+	for {
+		next = (next + 1) & m.mask
+		k := m.keys[next]
+		if k == freeKey {
+			break
+		}
+		home := MixKey(k) & m.mask
+		if ptr <= next {
+			if ptr < home && home <= next {
+				continue
+			}
+		} else if ptr < home || home <= next {
+			continue
+		}
+		m.keys[ptr] = k
+		m.data[ptr] = m.data[next]
+		ptr = next
+	}
+	m.keys[ptr] = freeKey
+	m.data[ptr] = zero
+}
+
+// Iterator returns a function iterating over all map entries; the third return value is false once exhausted.
+func (m *KeyMap[K, T]) Iterator() func() (K, T, bool) {
+	i := 0
+	foundFreeKey := false
+	return func() (K, T, bool) {
+		var freeKey K
+		if !foundFreeKey {
+			foundFreeKey = true
+			if m.hasFreeKey {
+				return freeKey, m.freeVal, true
+			}
+		}
+		for i < len(m.keys) {
+			key := m.keys[i]
+			value := m.data[i]
+			i++
+			if key != freeKey {
+				return key, value, true
+			}
+		}
+		var zero T
+		return freeKey, zero, false
+	}
+}
+
+// rehash doubles the computeCapacity and reinserts all existing keys and values.
+func (m *KeyMap[K, T]) rehash() {
+	atomic.AddUint32(&m.scn, 1)
+	newCapacity := len(m.keys) * 2
+	m.mask = uint64(newCapacity - 1)
+	m.threshold = int(math.Floor(float64(newCapacity) * m.fillFactor))
+	m.cap = uint32(newCapacity)
+	oldKeys := m.keys
+	oldData := m.data
+	m.keys = make([]K, newCapacity)
+	m.data = make([]T, newCapacity)
+	m.size = 0
+	if m.hasFreeKey {
+		m.size = 1
+	}
+	var freeKey K
+	for i, k := range oldKeys {
+		if k != freeKey {
+			m.Put(k, oldData[i])
+		}
+	}
+}
+
+// SCN returns the current sequence number of the map
+func (m *KeyMap[K, T]) SCN() int {
+	return int(atomic.LoadUint32(&m.scn))
+}
+
+// Size returns the number of elements in the map.
+func (m *KeyMap[K, T]) Size() int {
+	if m == nil {
+		return 0
+	}
+	return m.size
+}
+
+// Cap returns the computeCapacity of the map.
+func (m *KeyMap[K, T]) Cap() int {
+	if m == nil {
+		return 0
+	}
+	return int(atomic.LoadUint32(&m.cap))
+}
+
+// NewKeyMap creates a new KeyMap with the specified expected size and fill factor.
+// The fill factor must be between 0 and 1 (exclusive), and determines when the map will be resized.
+func NewKeyMap[K Key, T any](expectedSize int, fillFactor float64) *KeyMap[K, T] {
+	if fillFactor <= 0 || fillFactor >= 1 {
+		panic("FillFactor must be in (0, 1)")
+	}
+	if expectedSize <= 0 {
+		panic("Size must be positive")
+	}
+	capacity := computeCapacity(expectedSize, fillFactor)
+	return &KeyMap[K, T]{
+		keys:       make([]K, capacity),
+		data:       make([]T, capacity),
+		fillFactor: fillFactor,
+		threshold:  int(math.Floor(float64(capacity) * fillFactor)),
+		mask:       uint64(capacity - 1),
+		cap:        uint32(capacity),
+	}
+}
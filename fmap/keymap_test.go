@@ -0,0 +1,132 @@
+package fmap
+
+import (
+	"testing"
+)
+
+// testKeyMap puts, gets and deletes the supplied keys on a fresh KeyMap.
+func testKeyMap[K Key](t *testing.T, name string, keys []K) {
+	m := NewKeyMap[K, int](4, 0.75)
+	for i, key := range keys {
+		m.Put(key, i+1)
+		if m.Size() != i+1 {
+			t.Errorf("Test %s: Expected size=%d, got %d", name, i+1, m.Size())
+		}
+	}
+	for i, key := range keys {
+		val, found := m.Get(key)
+		if !found || val != i+1 {
+			t.Errorf("Test %s: Expected key %v with value=%d, got %d, %v", name, key, i+1, val, found)
+		}
+	}
+	count := 0
+	next := m.Iterator()
+	for {
+		_, _, hasMore := next()
+		if !hasMore {
+			break
+		}
+		count++
+	}
+	if count != len(keys) {
+		t.Errorf("Test %s: Expected %d iterated entries, got %d", name, len(keys), count)
+	}
+	for i, key := range keys {
+		if i%2 == 0 {
+			if val, found := m.Delete(key); !found || val != i+1 {
+				t.Errorf("Test %s: Expected deleted key %v with value=%d, got %d, %v", name, key, i+1, val, found)
+			}
+		}
+	}
+	for i, key := range keys {
+		_, found := m.Get(key)
+		if found != (i%2 == 1) {
+			t.Errorf("Test %s: Expected key %v found=%v, got %v", name, key, i%2 == 1, found)
+		}
+	}
+}
+
+// TestKeyMap_DataDriven tests KeyMap across supported key types.
+func TestKeyMap_DataDriven(t *testing.T) {
+	var uint32Keys []uint32
+	var int32Keys []int32
+	var uint64Keys []uint64
+	var uuidKeys []UUID
+	for i := 0; i < 1000; i++ {
+		uint32Keys = append(uint32Keys, uint32(i)*65536)
+		int32Keys = append(int32Keys, int32(i)-500)
+		uint64Keys = append(uint64Keys, uint64(i)<<40|uint64(i))
+		uuid := UUID{}
+		uuid[0] = byte(i)
+		uuid[15] = byte(i >> 8)
+		uuidKeys = append(uuidKeys, uuid)
+	}
+	testKeyMap(t, "uint32", uint32Keys)
+	testKeyMap(t, "int32", int32Keys)
+	testKeyMap(t, "uint64", uint64Keys)
+	testKeyMap(t, "uuid", uuidKeys)
+}
+
+// TestMixKey verifies that int64 keys hash the same way as in FastMap.
+func TestMixKey(t *testing.T) {
+	for _, key := range []int64{1, -1, 17, 1123123123123123} {
+		if MixKey(key) != uint64(phiMix(key)) {
+			t.Errorf("Expected MixKey(%d) to match phiMix", key)
+		}
+	}
+}
+
+func BenchmarkKeyMap_Get(b *testing.B) {
+	m := NewKeyMap[uint64, int](1<<16, 0.75)
+	for i := uint64(1); i <= 1<<16; i++ {
+		m.Put(i*7, int(i))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(uint64(i&(1<<16-1)) * 7)
+	}
+}
+
+func BenchmarkFastMap_Get(b *testing.B) {
+	m := NewFastMap[int](1<<16, 0.75)
+	for i := int64(1); i <= 1<<16; i++ {
+		m.Put(i*7, int(i))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(int64(i&(1<<16-1)) * 7)
+	}
+}
+
+// TestKeyMap_GetOrInsert tests single probe insertion across growth, including the zero key.
+func TestKeyMap_GetOrInsert(t *testing.T) {
+	m := NewKeyMap[UUID, int](4, 0.75)
+	moves := 0
+	for i := 0; i < 100; i++ {
+		key := UUID{byte(i)}
+		ptr, found, moved := m.GetOrInsert(key, func() int { return i })
+		if found || *ptr != i {
+			t.Errorf("Expected key %v inserted with value=%d, got %d, %v", key, i, *ptr, found)
+		}
+		if moved {
+			moves++
+		}
+		*ptr += 1000
+	}
+	if moves == 0 {
+		t.Errorf("Expected growth to move entries")
+	}
+	for i := 0; i < 100; i++ {
+		key := UUID{byte(i)}
+		ptr, found, moved := m.GetOrInsert(key, func() int {
+			t.Fatalf("unexpected insert of %v", key)
+			return 0
+		})
+		if !found || moved || *ptr != i+1000 {
+			t.Errorf("Expected key %v with value=%d, got %d, %v, %v", key, i+1000, *ptr, found, moved)
+		}
+	}
+	if m.Size() != 100 {
+		t.Errorf("Expected size=100, got %d", m.Size())
+	}
+}
@@ -1,6 +1,7 @@
 package fmap
 
 import (
+	"fmt"
 	"math"
 	"sync/atomic"
 )
@@ -21,7 +22,7 @@ func phiMix(x int64) int64 {
 
 // FastMap is a high-performance hash map for int64 keys and numeric values.
 // It uses open addressing with linear probing and a custom hash function for int64 keys.
-// The map is generic over the value type T, which must satisfy the Numeric interface.
+// The map is generic over any value type T; CounterMap adds arithmetic for numeric values.
 // This implementation is optimized for performance and low memory overhead, and is not safe for concurrent use.
 type FastMap[T any] struct {
 	keys       []int64 // Array of keys
@@ -34,8 +35,22 @@ type FastMap[T any] struct {
 	hasFreeKey bool  // Indicates if the map contains the FREE_KEY
 	freeVal    T     // Value associated with the FREE_KEY
 	scn        uint32
+	probing    probing // Collision resolution strategy
+	ctrl       []uint8 // Control bytes with hash tags, used by group probing only
+	tombstones int     // Number of deleted control bytes, used by group probing only
+	hasher     *hasher // Custom key hash function, phiMix when nil
+	hashFn     func(int64) uint64
+	rehashes   uint32 // Number of times slots were rehashed
+	offHeap    bool   // Indicates if slots are allocated outside the Go heap
+	region     []byte // Memory region holding off-heap slots
+	id         uint64 // Identifier of the map instance, zero when unknown
+	cloneOf    uint64 // Identifier of the map this map was cloned from
+	cloneSCN   uint32 // SCN of the map this map was cloned from at the time of cloning
 }
 
+// mapIDs generates FastMap identifiers used to track clones
+var mapIDs atomic.Uint64
+
 // nextPowerOf2 returns the next power of two greater than or equal to x.
 // It is used to ensure that the computeCapacity of the map is always a power of two.
 func nextPowerOf2(x uint64) uint64 {
@@ -81,10 +96,16 @@ func (m *FastMap[T]) Get(key int64) (T, bool) {
 		}
 		return zero, false
 	}
+	switch m.probing {
+	case robinHoodProbing:
+		return m.getRobinHood(key)
+	case groupProbing:
+		return m.getGroup(key)
+	}
 	keys := m.keys
 	data := m.data
 
-	ptr := phiMix(key) & m.mask
+	ptr := m.hash(key) & m.mask
 	k := keys[ptr]
 
 	if k == FREE_KEY {
@@ -116,8 +137,14 @@ func (m *FastMap[T]) GetPointer(key int64) (*T, bool) {
 		}
 		return nil, false
 	}
+	if m.probing != linearProbing {
+		if ptr := m.find(key); ptr >= 0 {
+			return &m.data[ptr], true
+		}
+		return nil, false
+	}
 
-	ptr := phiMix(key) & m.mask
+	ptr := m.hash(key) & m.mask
 	k := m.keys[ptr]
 
 	if k == FREE_KEY {
@@ -147,15 +174,19 @@ func (m *FastMap[T]) SCN() int {
 // Put adds or updates the key with the value val.
 func (m *FastMap[T]) Put(key int64, val T) {
 	if key == FREE_KEY {
-		if !m.hasFreeKey {
-			m.size++
-		}
-		m.hasFreeKey = true
-		m.freeVal = val
+		m.putFreeKey(val)
+		return
+	}
+	switch m.probing {
+	case robinHoodProbing:
+		m.putRobinHood(key, val)
+		return
+	case groupProbing:
+		m.putGroup(key, val)
 		return
 	}
 
-	ptr := phiMix(key) & m.mask
+	ptr := m.hash(key) & m.mask
 	k := m.keys[ptr]
 
 	if k == FREE_KEY { // Empty slot found
@@ -192,6 +223,77 @@ func (m *FastMap[T]) Put(key int64, val T) {
 	}
 }
 
+// Delete removes the key from the map.
+// It returns the removed value and a boolean indicating whether the key was found.
+// Deletion uses backward-shift compaction, so no tombstones are left behind and probe chains stay intact.
+func (m *FastMap[T]) Delete(key int64) (T, bool) {
+	var zero T
+	if key == FREE_KEY {
+		if !m.hasFreeKey {
+			return zero, false
+		}
+		val := m.freeVal
+		m.removeFreeKey()
+		return val, true
+	}
+	ptr := m.find(key)
+	if ptr < 0 {
+		return zero, false
+	}
+	val := m.data[ptr]
+	m.removeAt(ptr)
+	return val, true
+}
+
+// shiftBackward empties the slot at ptr and moves subsequent entries of the probe chain back
+// into the gap, as long as doing so does not move an entry before its home slot.
+func (m *FastMap[T]) shiftBackward(ptr int64) {
+	var zero T
+	next := ptr
+	for {
+		next = (next + 1) & m.mask
+		k := m.keys[next]
+		if k == FREE_KEY {
+			break
+		}
+		home := m.hash(k) & m.mask
+		// skip entries whose home lies cyclically in (ptr, next], they cannot move into the gap
+		if ptr <= next {
+			if ptr < home && home <= next {
+				continue
+			}
+		} else if ptr < home || home <= next {
+			continue
+		}
+		m.keys[ptr] = k
+		m.data[ptr] = m.data[next]
+		ptr = next
+	}
+	m.keys[ptr] = FREE_KEY
+	m.data[ptr] = zero
+}
+
+// find returns the slot holding the given non FREE_KEY key, or -1 if the key is absent.
+func (m *FastMap[T]) find(key int64) int64 {
+	switch m.probing {
+	case robinHoodProbing:
+		return m.findRobinHood(key)
+	case groupProbing:
+		return m.findGroup(key)
+	}
+	ptr := m.hash(key) & m.mask
+	for {
+		k := m.keys[ptr]
+		if k == key {
+			return ptr
+		}
+		if k == FREE_KEY {
+			return -1
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+}
+
 // Value returns the key and value at the given pointer.
 //
 //go:inline
@@ -231,19 +333,24 @@ func (m *FastMap[T]) Iterator() func() (int, T, bool) {
 // rehash resizes the map when the load factor exceeds the threshold.
 // It doubles the computeCapacity and reinserts all existing keys and values.
 func (m *FastMap[T]) rehash() {
+	m.resize(len(m.keys) * 2)
+}
+
+// resize reinserts all existing keys and values into slots of the given computeCapacity.
+func (m *FastMap[T]) resize(newCapacity int) {
 	atomic.AddUint32(&m.scn, 1)
-	newCapacity := len(m.keys) * 2
+	m.rehashes++
 	// Update mask and threshold based on new computeCapacity
-	m.mask = int64(newCapacity - 1)
-	m.threshold = int(math.Floor(float64(newCapacity) * m.fillFactor))
-	m.cap = uint32(newCapacity)
+	m.setCapacity(newCapacity)
 	// Save old data
 	oldKeys := m.keys
 	oldData := m.data
 
+	oldRegion := m.region
+
 	// Create new slices with updated computeCapacity
-	m.keys = make([]int64, newCapacity)
-	m.data = make([]T, newCapacity)
+	m.allocate(newCapacity)
+	m.tombstones = 0
 
 	// Reset size and re-insert keys
 	m.size = 0
@@ -257,22 +364,81 @@ func (m *FastMap[T]) rehash() {
 			m.Put(k, oldData[i])
 		}
 	}
+	releaseRegion(oldRegion)
+}
+
+// setCapacity updates mask, threshold and cap for the given computeCapacity
+func (m *FastMap[T]) setCapacity(capacity int) {
+	m.mask = int64(capacity - 1)
+	m.threshold = int(math.Floor(float64(capacity) * m.fillFactor))
+	atomic.StoreUint32(&m.cap, uint32(capacity))
+}
 
+// capacityFor returns the smallest computeCapacity holding size elements without rehashing
+func (m *FastMap[T]) capacityFor(size int) int {
+	capacity := computeCapacity(size+1, m.fillFactor)
+	if m.probing == groupProbing && capacity < groupSize {
+		capacity = groupSize // group probing needs at least one full group
+	}
+	return capacity
 }
 
+// Clear empties the map and resizes it for expectedSize elements.
+// keys and data are ignored; the map neither reads nor writes them and keeps using its own slot storage.
+//
+// Deprecated: use Reset to empty the map, and Reserve or Shrink to resize it.
 func (m *FastMap[T]) Clear(expectedSize int, keys []int64, data []T) {
-	capacity := computeCapacity(expectedSize, m.fillFactor)
-	if len(m.keys) > capacity {
-		m.keys = m.keys[:capacity]
-		m.data = m.data[:capacity]
+	if capacity := m.capacityFor(expectedSize); capacity != len(m.keys) {
+		oldRegion := m.region
+		m.allocate(capacity)
+		releaseRegion(oldRegion)
+		m.setCapacity(capacity)
+	}
+	m.Reset()
+}
+
+// Reset removes all elements from the map, keeping its computeCapacity.
+func (m *FastMap[T]) Reset() {
+	var zero T
+	clear(m.keys)
+	clear(m.data)
+	for i := range m.ctrl {
+		m.ctrl[i] = ctrlEmpty
 	}
-	copy(m.keys, keys)
-	copy(m.data, data)
 	m.size = 0
-	m.scn = 0
+	m.tombstones = 0
 	m.hasFreeKey = false
-	m.cap = uint32(capacity)
-	m.mask = int64(capacity - 1)
+	m.freeVal = zero
+	atomic.AddUint32(&m.scn, 1) //removed all keys
+}
+
+// Reserve grows the map, if needed, so that it holds n elements in total without rehashing.
+func (m *FastMap[T]) Reserve(n int) {
+	if capacity := m.capacityFor(n); capacity > len(m.keys) {
+		m.resize(capacity)
+	}
+}
+
+// Shrink rehashes the map down to the smallest computeCapacity that fits its elements, which releases memory
+// after mass deletion; group probing maps also drop their tombstones.
+func (m *FastMap[T]) Shrink() {
+	if capacity := m.capacityFor(m.size); capacity < len(m.keys) || m.tombstones > 0 {
+		m.resize(capacity)
+	}
+}
+
+// Clone returns a point-in-time copy of the map sharing no memory with the original.
+// Slots are copied in bulk, so the copy keeps the exact probing layout and SCN and needs no rehashing.
+func (m *FastMap[T]) Clone() *FastMap[T] {
+	c := *m
+	c.id = mapIDs.Add(1)
+	c.cloneOf, c.cloneSCN = m.id, atomic.LoadUint32(&m.scn)
+	c.region = nil
+	c.allocate(len(m.keys))
+	copy(c.keys, m.keys)
+	copy(c.data, m.data)
+	copy(c.ctrl, m.ctrl)
+	return &c
 }
 
 // Size returns the number of elements in the map.
@@ -293,8 +459,8 @@ func (m *FastMap[T]) Cap() int {
 
 // NewNumericMap creates a new FastMap with the specified expected size and fill factor.
 // The fill factor must be between 0 and 1 (exclusive), and determines when the map will be resized.
-// The map will grow automatically as needed.
-func NewFastMap[T any](expectedSize int, fillFactor float64) *FastMap[T] {
+// The map will grow automatically as needed; options control how collisions are resolved.
+func NewFastMap[T any](expectedSize int, fillFactor float64, opts ...Option) *FastMap[T] {
 	if fillFactor <= 0 || fillFactor >= 1 {
 		panic("FillFactor must be in (0, 1)")
 	}
@@ -302,14 +468,26 @@ func NewFastMap[T any](expectedSize int, fillFactor float64) *FastMap[T] {
 		panic("Size must be positive")
 	}
 
+	o := newOptions(opts)
 	capacity := computeCapacity(expectedSize, fillFactor)
+	if o.probing == groupProbing && capacity < groupSize {
+		capacity = groupSize // group probing needs at least one full group
+	}
+	if o.offHeap {
+		if err := ensurePointerFree[T](); err != nil {
+			panic(fmt.Sprintf("unable to allocate off heap: %v", err))
+		}
+	}
 	m := &FastMap[T]{
-		keys:       make([]int64, capacity),
-		data:       make([]T, capacity),
 		fillFactor: fillFactor,
 		threshold:  int(math.Floor(float64(capacity) * fillFactor)),
 		mask:       int64(capacity - 1),
 		cap:        uint32(capacity),
+		probing:    o.probing,
+		offHeap:    o.offHeap,
+		id:         mapIDs.Add(1),
 	}
+	m.useHasher(o.hasher)
+	m.allocate(capacity)
 	return m
 }
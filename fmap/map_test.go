@@ -276,3 +276,186 @@ func TestFastMapDifferentTypesDataDriven(t *testing.T) {
 		})
 	}
 }
+
+// TestFastMap_DeleteDataDriven tests key deletion using a data-driven approach.
+func TestFastMap_DeleteDataDriven(t *testing.T) {
+	testCases := []struct {
+		name        string
+		insert      []int64
+		delete      int64
+		expectValue int
+		expectFound bool
+		expectSize  int
+	}{
+		{
+			name:        "DeleteExisting",
+			insert:      []int64{1, 2, 3},
+			delete:      2,
+			expectValue: 20,
+			expectFound: true,
+			expectSize:  2,
+		},
+		{
+			name:        "DeleteMissing",
+			insert:      []int64{1, 2, 3},
+			delete:      4,
+			expectFound: false,
+			expectSize:  3,
+		},
+		{
+			name:        "DeleteFreeKey",
+			insert:      []int64{0, 1, 2},
+			delete:      0,
+			expectValue: 0,
+			expectFound: true,
+			expectSize:  2,
+		},
+		{
+			name:        "DeleteFromCollisionChain",
+			insert:      []int64{1, 17, 33, 49, 65},
+			delete:      17,
+			expectValue: 170,
+			expectFound: true,
+			expectSize:  4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewFastMap[int](4, 0.75)
+			for _, key := range tc.insert {
+				m.Put(key, int(key)*10)
+			}
+			scn := m.SCN()
+			val, found := m.Delete(tc.delete)
+			if found != tc.expectFound {
+				t.Errorf("Test %s: Expected found=%v, got %v", tc.name, tc.expectFound, found)
+			}
+			if val != tc.expectValue {
+				t.Errorf("Test %s: Expected value=%v, got %v", tc.name, tc.expectValue, val)
+			}
+			if m.Size() != tc.expectSize {
+				t.Errorf("Test %s: Expected size=%d, got %d", tc.name, tc.expectSize, m.Size())
+			}
+			if found && m.SCN() == scn {
+				t.Errorf("Test %s: Expected SCN change after delete", tc.name)
+			}
+			if _, ok := m.Get(tc.delete); ok {
+				t.Errorf("Test %s: Expected key %d to be absent", tc.name, tc.delete)
+			}
+			for _, key := range tc.insert {
+				if key == tc.delete {
+					continue
+				}
+				if val, ok := m.Get(key); !ok || val != int(key)*10 {
+					t.Errorf("Test %s: Expected key %d with value %d, got %d, %v", tc.name, key, key*10, val, ok)
+				}
+			}
+		})
+	}
+}
+
+// TestFastMap_DeleteProbeChains deletes and reinserts many keys and verifies that lookups stay correct.
+func TestFastMap_DeleteProbeChains(t *testing.T) {
+	m := NewFastMap[int64](16, 0.9)
+	expect := map[int64]int64{}
+	for i := int64(1); i <= 5000; i++ {
+		key := i * 65536
+		m.Put(key, i)
+		expect[key] = i
+	}
+	for i := int64(1); i <= 5000; i += 3 {
+		key := i * 65536
+		if _, ok := m.Delete(key); !ok {
+			t.Fatalf("Expected key %d to be deleted", key)
+		}
+		delete(expect, key)
+	}
+	if m.Size() != len(expect) {
+		t.Errorf("Expected size=%d, got %d", len(expect), m.Size())
+	}
+	for i := int64(1); i <= 5000; i++ {
+		key := i * 65536
+		val, ok := m.Get(key)
+		expectVal, expectOk := expect[key]
+		if ok != expectOk || val != expectVal {
+			t.Errorf("Key %d: expected %d, %v, got %d, %v", key, expectVal, expectOk, val, ok)
+		}
+	}
+}
+
+// checkCapacity verifies that mask, threshold and cap agree with the slots
+func checkCapacity[T any](t *testing.T, m *FastMap[T], expectCap int) {
+	t.Helper()
+	if len(m.keys) != expectCap || len(m.data) != expectCap || m.Cap() != expectCap || m.mask != int64(expectCap-1) {
+		t.Errorf("Expected capacity %d, got keys=%d data=%d cap=%d mask=%d", expectCap, len(m.keys), len(m.data), m.Cap(), m.mask)
+	}
+	if m.threshold != int(float64(expectCap)*m.fillFactor) {
+		t.Errorf("Expected threshold for capacity %d, got %d", expectCap, m.threshold)
+	}
+}
+
+// TestFastMap_ResetReserveShrink verifies capacity management across probing modes.
+func TestFastMap_ResetReserveShrink(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[int64](8, 0.75, mode.opts...)
+			m.Reserve(1000)
+			checkCapacity(t, m, 2048)
+			rehashes := m.rehashes
+			for i := int64(0); i < 1000; i++ {
+				m.Put(i, i)
+			}
+			if m.rehashes != rehashes {
+				t.Errorf("Expected no rehash after Reserve")
+			}
+			for i := int64(10); i < 1000; i++ {
+				m.Delete(i)
+			}
+			scn := m.SCN()
+			m.Shrink()
+			checkCapacity(t, m, 16)
+			if m.SCN() == scn {
+				t.Errorf("Expected SCN change on Shrink")
+			}
+			for i := int64(0); i < 1000; i++ {
+				val, ok := m.Get(i)
+				if ok != (i < 10) || (ok && val != i) {
+					t.Errorf("Key %d: got %d, %v", i, val, ok)
+				}
+			}
+
+			scn = m.SCN()
+			m.Reset()
+			checkCapacity(t, m, 16)
+			if m.Size() != 0 || m.SCN() == scn {
+				t.Errorf("Expected empty map with new SCN, got size=%d", m.Size())
+			}
+			if _, ok := m.Get(0); ok {
+				t.Errorf("Expected FREE_KEY to be removed")
+			}
+			for i := int64(0); i < 100; i++ {
+				m.Put(i, -i)
+			}
+			if val, ok := m.Get(99); !ok || val != -99 {
+				t.Errorf("Expected -99 after Reset, got %d, %v", val, ok)
+			}
+
+			keys, data := make([]int64, 256), make([]int64, 256)
+			keys[3], data[3] = 3, 3
+			m.Clear(100, keys, data)
+			checkCapacity(t, m, 256)
+			m.Put(7, 7)
+			if _, ok := m.Get(3); ok || keys[3] != 3 || data[3] != 3 {
+				t.Errorf("Expected Clear to leave keys and data untouched")
+			}
+			if val, ok := m.Get(7); !ok || val != 7 || m.Size() != 1 {
+				t.Errorf("Expected 7 after Clear, got %d, %v, size=%d", val, ok, m.Size())
+			}
+			m.Clear(1, nil, nil)
+			if m.Size() != 0 || m.Cap() > 8 {
+				t.Errorf("Expected small empty map after Clear, got size=%d cap=%d", m.Size(), m.Cap())
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+package fmap
+
+import (
+	"fmt"
+	"hash/crc64"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"unsafe"
+)
+
+const (
+	mappedMagic      uint32 = 0x50414D46 // "FMAP" in little endian byte order
+	mappedVersion    uint16 = 2          // Version 2 added probing flags, control bytes and the hasher
+	mappedVersionV1  uint16 = 1          // Version 1 files hold linear probing maps hashed with phiMix
+	mappedHeaderSize        = 64
+	mappedFreeKey    uint16 = 1 // Flag set when the map contains FREE_KEY
+	mappedRobinHood  uint16 = 2 // Flag set when the map uses Robin Hood probing
+	mappedGroup      uint16 = 4 // Flag set when the map uses group probing, control bytes follow the FREE_KEY value
+)
+
+var crcTable = crc64.MakeTable(crc64.ECMA)
+
+// mappedHeader represents the header of a memory mappable FastMap file.
+// The file is written in native byte order: header, keys, data, the FREE_KEY value and optional control bytes,
+// each aligned to 8 bytes.
+type mappedHeader struct {
+	Magic      uint32
+	Version    uint16
+	Flags      uint16
+	ValueSize  uint32
+	HasherKind uint32
+	Mask       uint64
+	Size       uint64
+	FillFactor float64
+	Checksum   uint64 // CRC-64 of everything following the header
+	HasherID   uint64 // Seed of seeded hasher, or name hash of registered hasher
+	_          [8]byte
+}
+
+// align8 rounds n up to the multiple of 8
+func align8(n int) int {
+	return (n + 7) &^ 7
+}
+
+// mappedLayout returns offsets of data, FREE_KEY value and control bytes and the total file size for the given capacity
+func mappedLayout(capacity int, valueSize int, hasCtrl bool) (dataOffset, freeValOffset, ctrlOffset, fileSize int) {
+	dataOffset = mappedHeaderSize + capacity*8
+	freeValOffset = align8(dataOffset + capacity*valueSize)
+	ctrlOffset = align8(freeValOffset + valueSize)
+	fileSize = ctrlOffset
+	if hasCtrl {
+		fileSize = align8(ctrlOffset + capacity)
+	}
+	return dataOffset, freeValOffset, ctrlOffset, fileSize
+}
+
+// ensurePointerFree returns an error if T contains pointers
+func ensurePointerFree[T any]() error {
+	var zero T
+	if rType := reflect.TypeOf(&zero).Elem(); hasPointers(rType) {
+		return fmt.Errorf("unsupported type %v: type must not contain pointers", rType)
+	}
+	return nil
+}
+
+// WriteMapped writes the map in the memory mappable layout, which can be served with OpenMapped.
+// T must not contain pointers.
+func (m *FastMap[T]) WriteMapped(writer io.Writer) (int64, error) {
+	if err := ensurePointerFree[T](); err != nil {
+		return 0, err
+	}
+	var zero T
+	valueSize := int(unsafe.Sizeof(zero))
+	capacity := len(m.keys)
+	hasCtrl := m.probing == groupProbing
+	dataOffset, freeValOffset, ctrlOffset, fileSize := mappedLayout(capacity, valueSize, hasCtrl)
+	var padding [8]byte
+	sections := [][]byte{
+		rawBytes(m.keys),
+		rawBytes(m.data),
+		padding[:freeValOffset-dataOffset-capacity*valueSize],
+		rawBytes([]T{m.freeVal}),
+		padding[:ctrlOffset-freeValOffset-valueSize],
+	}
+	if hasCtrl {
+		sections = append(sections, m.ctrl, padding[:fileSize-ctrlOffset-capacity])
+	}
+	header := mappedHeader{
+		Magic:      mappedMagic,
+		Version:    mappedVersion,
+		ValueSize:  uint32(valueSize),
+		Mask:       uint64(m.mask),
+		Size:       uint64(m.size),
+		FillFactor: m.fillFactor,
+		Checksum:   checksum(sections),
+	}
+	if m.hasFreeKey {
+		header.Flags |= mappedFreeKey
+	}
+	if m.hasher != nil {
+		header.HasherKind = uint32(m.hasher.kind)
+		header.HasherID = m.hasher.id()
+	}
+	switch m.probing {
+	case robinHoodProbing:
+		header.Flags |= mappedRobinHood
+	case groupProbing:
+		header.Flags |= mappedGroup
+	}
+	return writeSections(writer, unsafe.Slice((*byte)(unsafe.Pointer(&header)), mappedHeaderSize), sections)
+}
+
+// checksum returns CRC-64 of the sections
+func checksum(sections [][]byte) uint64 {
+	hash := crc64.New(crcTable)
+	for _, section := range sections {
+		hash.Write(section)
+	}
+	return hash.Sum64()
+}
+
+// writeSections writes the header followed by the sections
+func writeSections(writer io.Writer, header []byte, sections [][]byte) (int64, error) {
+	written, err := writer.Write(header)
+	if err != nil {
+		return int64(written), err
+	}
+	for _, section := range sections {
+		n, err := writer.Write(section)
+		written += n
+		if err != nil {
+			return int64(written), err
+		}
+	}
+	return int64(written), nil
+}
+
+// MappedMap represents a read-only FastMap served directly from a memory mapped file.
+type MappedMap[T any] struct {
+	m    FastMap[T]
+	data []byte
+}
+
+// Get retrieves the value associated with the given key.
+// It returns the value and a boolean indicating whether the key was found.
+func (m *MappedMap[T]) Get(key int64) (T, bool) {
+	return m.m.Get(key)
+}
+
+// Iterator returns a function iterating over all map entries; the third return value is false once exhausted.
+func (m *MappedMap[T]) Iterator() func() (int, T, bool) {
+	return m.m.Iterator()
+}
+
+// Size returns the number of elements in the map.
+func (m *MappedMap[T]) Size() int {
+	return m.m.Size()
+}
+
+// Cap returns the computeCapacity of the map.
+func (m *MappedMap[T]) Cap() int {
+	return m.m.Cap()
+}
+
+// Close unmaps the file; the map must not be used afterwards.
+func (m *MappedMap[T]) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	data := m.data
+	m.data = nil
+	m.m = FastMap[T]{}
+	return unmapFile(data)
+}
+
+// OpenMapped maps the file written by FastMap.WriteMapped and serves lookups from the mapped keys and data arrays.
+// T must not contain pointers and has to be the same type the file was written with.
+func OpenMapped[T any](path string) (*MappedMap[T], error) {
+	if err := ensurePointerFree[T](); err != nil {
+		return nil, err
+	}
+	data, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ret := &MappedMap[T]{data: data}
+	if err = ret.init(); err != nil {
+		_ = unmapFile(data)
+		return nil, fmt.Errorf("invalid mapped FastMap file %v: %w", path, err)
+	}
+	return ret, nil
+}
+
+// openFile maps the file holding at least the header into memory
+func openFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < mappedHeaderSize {
+		return nil, fmt.Errorf("invalid mapped file %v: too short", path)
+	}
+	return mapFile(file, int(info.Size()))
+}
+
+// init validates the header and checksum and points map slots to the mapped memory
+func (m *MappedMap[T]) init() error {
+	header := (*mappedHeader)(unsafe.Pointer(&m.data[0]))
+	if header.Magic != mappedMagic {
+		return fmt.Errorf("invalid magic: %x", header.Magic)
+	}
+	flags, kind, id := header.Flags, hasherKind(header.HasherKind), header.HasherID
+	switch header.Version {
+	case mappedVersion:
+	case mappedVersionV1:
+		flags, kind, id = flags&mappedFreeKey, phiHasher, 0
+	default:
+		return fmt.Errorf("unsupported version: %v", header.Version)
+	}
+	var zero T
+	valueSize := int(unsafe.Sizeof(zero))
+	if int(header.ValueSize) != valueSize {
+		return fmt.Errorf("value size mismatch: expected %v, but had %v", valueSize, header.ValueSize)
+	}
+	capacity := header.Mask + 1
+	if capacity < 2 || capacity&header.Mask != 0 || capacity > math.MaxUint32 {
+		return fmt.Errorf("invalid mask: %v", header.Mask)
+	}
+	hasCtrl := flags&mappedGroup != 0
+	dataOffset, freeValOffset, ctrlOffset, fileSize := mappedLayout(int(capacity), valueSize, hasCtrl)
+	if fileSize != len(m.data) {
+		return fmt.Errorf("size mismatch: expected %v, but had %v", fileSize, len(m.data))
+	}
+	if checksum := crc64.Checksum(m.data[mappedHeaderSize:], crcTable); checksum != header.Checksum {
+		return fmt.Errorf("checksum mismatch")
+	}
+	hasher, err := lookupHasher(kind, "", id)
+	if err != nil {
+		return err
+	}
+	m.m = FastMap[T]{
+		keys:       unsafe.Slice((*int64)(unsafe.Pointer(&m.data[mappedHeaderSize])), capacity),
+		fillFactor: header.FillFactor,
+		threshold:  int(math.Floor(float64(capacity) * header.FillFactor)),
+		size:       int(header.Size),
+		cap:        uint32(capacity),
+		mask:       int64(header.Mask),
+		hasFreeKey: flags&mappedFreeKey != 0,
+	}
+	m.m.useHasher(hasher)
+	if flags&mappedRobinHood != 0 {
+		m.m.probing = robinHoodProbing
+	}
+	if hasCtrl {
+		m.m.probing = groupProbing
+		m.m.ctrl = m.data[ctrlOffset : ctrlOffset+int(capacity)]
+	}
+	if valueSize > 0 {
+		m.m.data = unsafe.Slice((*T)(unsafe.Pointer(&m.data[dataOffset])), capacity)
+		m.m.freeVal = *(*T)(unsafe.Pointer(&m.data[freeValOffset]))
+	} else {
+		m.m.data = make([]T, capacity)
+	}
+	return nil
+}
+
+const (
+	frozenMagic   uint32 = 0x4E5A5246 // "FRZN" in little endian byte order
+	frozenVersion uint16 = 1
+)
+
+// frozenHeader represents the header of a memory mappable FrozenMap file.
+// The file is written in native byte order: header, bucket displacements, keys, data and the FREE_KEY value,
+// each aligned to 8 bytes.
+type frozenHeader struct {
+	Magic     uint32
+	Version   uint16
+	Flags     uint16
+	ValueSize uint32
+	_         uint32
+	Count     uint64
+	Buckets   uint64
+	Seed      uint64
+	Checksum  uint64 // CRC-64 of everything following the header
+	_         [16]byte
+}
+
+// frozenLayout returns offsets of keys, data and FREE_KEY value and the total file size
+func frozenLayout(buckets, count, valueSize int) (keysOffset, dataOffset, freeValOffset, fileSize int) {
+	keysOffset = align8(mappedHeaderSize + buckets*4)
+	dataOffset = keysOffset + count*8
+	freeValOffset = align8(dataOffset + count*valueSize)
+	fileSize = align8(freeValOffset + valueSize)
+	return keysOffset, dataOffset, freeValOffset, fileSize
+}
+
+// WriteMapped writes the map in the memory mappable layout, which can be served with OpenFrozen.
+// T must not contain pointers.
+func (f *FrozenMap[T]) WriteMapped(writer io.Writer) (int64, error) {
+	if err := ensurePointerFree[T](); err != nil {
+		return 0, err
+	}
+	var zero T
+	valueSize := int(unsafe.Sizeof(zero))
+	count := len(f.keys)
+	keysOffset, dataOffset, freeValOffset, fileSize := frozenLayout(len(f.disp), count, valueSize)
+	var padding [8]byte
+	sections := [][]byte{
+		rawBytes(f.disp),
+		padding[:keysOffset-mappedHeaderSize-len(f.disp)*4],
+		rawBytes(f.keys),
+		rawBytes(f.data),
+		padding[:freeValOffset-dataOffset-count*valueSize],
+		rawBytes([]T{f.freeVal}),
+		padding[:fileSize-freeValOffset-valueSize],
+	}
+	header := frozenHeader{
+		Magic:     frozenMagic,
+		Version:   frozenVersion,
+		ValueSize: uint32(valueSize),
+		Count:     uint64(count),
+		Buckets:   uint64(len(f.disp)),
+		Seed:      f.seed,
+		Checksum:  checksum(sections),
+	}
+	if f.hasFreeKey {
+		header.Flags |= mappedFreeKey
+	}
+	return writeSections(writer, unsafe.Slice((*byte)(unsafe.Pointer(&header)), mappedHeaderSize), sections)
+}
+
+// OpenFrozen maps the file written by FrozenMap.WriteMapped and serves lookups from the mapped memory.
+// T must not contain pointers and has to be the same type the file was written with.
+func OpenFrozen[T any](path string) (*FrozenMap[T], error) {
+	if err := ensurePointerFree[T](); err != nil {
+		return nil, err
+	}
+	data, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ret := &FrozenMap[T]{mapped: data}
+	if err = ret.init(); err != nil {
+		_ = unmapFile(data)
+		return nil, fmt.Errorf("invalid mapped FrozenMap file %v: %w", path, err)
+	}
+	return ret, nil
+}
+
+// init validates the header and checksum and points displacements and slots to the mapped memory
+func (f *FrozenMap[T]) init() error {
+	header := (*frozenHeader)(unsafe.Pointer(&f.mapped[0]))
+	if header.Magic != frozenMagic {
+		return fmt.Errorf("invalid magic: %x", header.Magic)
+	}
+	if header.Version != frozenVersion {
+		return fmt.Errorf("unsupported version: %v", header.Version)
+	}
+	var zero T
+	valueSize := int(unsafe.Sizeof(zero))
+	if int(header.ValueSize) != valueSize {
+		return fmt.Errorf("value size mismatch: expected %v, but had %v", valueSize, header.ValueSize)
+	}
+	if header.Buckets == 0 || header.Buckets > math.MaxUint32 || header.Count >= uint64(frozenDirect) {
+		return fmt.Errorf("invalid size: %v keys in %v buckets", header.Count, header.Buckets)
+	}
+	count := int(header.Count)
+	keysOffset, dataOffset, freeValOffset, fileSize := frozenLayout(int(header.Buckets), count, valueSize)
+	if fileSize != len(f.mapped) {
+		return fmt.Errorf("size mismatch: expected %v, but had %v", fileSize, len(f.mapped))
+	}
+	if checksum := crc64.Checksum(f.mapped[mappedHeaderSize:], crcTable); checksum != header.Checksum {
+		return fmt.Errorf("checksum mismatch")
+	}
+	f.seed = header.Seed
+	f.hasFreeKey = header.Flags&mappedFreeKey != 0
+	f.disp = unsafe.Slice((*uint32)(unsafe.Pointer(&f.mapped[mappedHeaderSize])), header.Buckets)
+	f.keys = unsafe.Slice((*int64)(unsafe.Pointer(&f.mapped[keysOffset])), count)
+	if valueSize > 0 {
+		f.data = unsafe.Slice((*T)(unsafe.Pointer(&f.mapped[dataOffset])), count)
+		f.freeVal = *(*T)(unsafe.Pointer(&f.mapped[freeValOffset]))
+	} else {
+		f.data = make([]T, count)
+	}
+	return f.validate()
+}
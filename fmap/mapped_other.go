@@ -0,0 +1,35 @@
+//go:build !unix
+
+package fmap
+
+import (
+	"io"
+	"os"
+	"unsafe"
+)
+
+// mapFile reads the file into 8 byte aligned memory, on platforms without mmap support
+func mapFile(file *os.File, size int) ([]byte, error) {
+	words := make([]uint64, (size+7)/8)
+	data := unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(words))), size)
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// unmapFile releases memory returned by mapFile
+func unmapFile(data []byte) error {
+	return nil
+}
+
+// allocRegion returns zeroed 8 byte aligned memory of the given size, on platforms without mmap support
+// the memory is allocated on the heap as a pointer-free array, which the garbage collector does not scan
+func allocRegion(size int) []byte {
+	words := make([]uint64, (size+7)/8)
+	return unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(words))), size)
+}
+
+// releaseRegion releases memory returned by allocRegion
+func releaseRegion(region []byte) {
+}
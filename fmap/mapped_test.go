@@ -0,0 +1,103 @@
+package fmap
+
+import (
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeMapped writes the map into a temp file and returns its path
+func writeMapped[T any](t *testing.T, m *FastMap[T]) string {
+	path := filepath.Join(t.TempDir(), "map.fmap")
+	file, err := os.Create(path)
+	if !assert.Nil(t, err) {
+		return path
+	}
+	defer file.Close()
+	_, err = m.WriteMapped(file)
+	assert.Nil(t, err)
+	return path
+}
+
+func TestOpenMapped(t *testing.T) {
+	m := NewFastMap[codecPoint](4, 0.75)
+	for i := int64(0); i < 1000; i++ {
+		m.Put(i*3, codecPoint{X: float32(i), ID: i})
+	}
+	path := writeMapped(t, m)
+
+	mapped, err := OpenMapped[codecPoint](path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, m.Size(), mapped.Size())
+	assert.Equal(t, m.Cap(), mapped.Cap())
+	for i := int64(0); i < 1000; i++ {
+		val, ok := mapped.Get(i * 3)
+		assert.True(t, ok)
+		assert.Equal(t, codecPoint{X: float32(i), ID: i}, val)
+	}
+	_, ok := mapped.Get(1)
+	assert.False(t, ok)
+	assert.Nil(t, mapped.Close())
+
+	_, err = OpenMapped[int32](path)
+	assert.NotNil(t, err, "value size mismatch")
+	_, err = OpenMapped[string](path)
+	assert.NotNil(t, err, "pointer type")
+
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	data[len(data)-9] ^= 0xFF
+	assert.Nil(t, os.WriteFile(path, data, 0644))
+	_, err = OpenMapped[codecPoint](path)
+	assert.NotNil(t, err, "checksum mismatch")
+
+	_, err = NewFastMap[string](4, 0.75).WriteMapped(new(os.File))
+	assert.NotNil(t, err)
+}
+
+func TestOpenMapped_Version(t *testing.T) {
+	m := NewFastMap[int64](4, 0.75)
+	for i := int64(0); i < 100; i++ {
+		m.Put(i, i*2)
+	}
+	path := writeMapped(t, m)
+	data, err := os.ReadFile(path)
+	if !assert.Nil(t, err) {
+		return
+	}
+	testCases := []struct {
+		name      string
+		version   uint16
+		expectErr bool
+	}{
+		{name: "Current", version: mappedVersion},
+		{name: "V1", version: mappedVersionV1},
+		{name: "Unknown", version: mappedVersion + 1, expectErr: true},
+		{name: "Zero", version: 0, expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			binary.LittleEndian.PutUint16(data[4:], tc.version)
+			assert.Nil(t, os.WriteFile(path, data, 0644))
+			mapped, err := OpenMapped[int64](path)
+			if tc.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer mapped.Close()
+			assert.Equal(t, m.Size(), mapped.Size())
+			for i := int64(0); i < 100; i++ {
+				val, ok := mapped.Get(i)
+				assert.True(t, ok)
+				assert.Equal(t, i*2, val)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package fmap
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mapFile maps size bytes of the file into read-only memory
+func mapFile(file *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// unmapFile releases memory returned by mapFile
+func unmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
+
+// allocRegion returns zeroed memory of the given size outside the Go heap
+func allocRegion(size int) []byte {
+	region, err := syscall.Mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		panic(fmt.Sprintf("unable to allocate %v bytes off heap: %v", size, err))
+	}
+	return region
+}
+
+// releaseRegion releases memory returned by allocRegion
+func releaseRegion(region []byte) {
+	if region != nil {
+		_ = syscall.Munmap(region)
+	}
+}
@@ -0,0 +1,166 @@
+package fmap
+
+import (
+	"bytes"
+	"cmp"
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// stringPreviewLimit is the maximum number of entries printed by String
+const stringPreviewLimit = 16
+
+// slotEntry represents a key and the slot holding its value, -1 for FREE_KEY
+type slotEntry struct {
+	key  int64
+	slot int64
+}
+
+// compareSlotEntries orders entries by key ascending
+func compareSlotEntries(a, b slotEntry) int {
+	return cmp.Compare(a.key, b.key)
+}
+
+// eachSlot calls fn with the key and slot of every entry, scanning slots once
+func (m *FastMap[T]) eachSlot(fn func(entry slotEntry)) {
+	if m.hasFreeKey {
+		fn(slotEntry{key: FREE_KEY, slot: -1})
+	}
+	for i, key := range m.keys {
+		if key != FREE_KEY {
+			fn(slotEntry{key: key, slot: int64(i)})
+		}
+	}
+}
+
+// slotValue returns the value of the entry
+func (m *FastMap[T]) slotValue(entry slotEntry) T {
+	if entry.slot < 0 {
+		return m.freeVal
+	}
+	return m.data[entry.slot]
+}
+
+// sortedEntries returns all entries in ascending key order
+func (m *FastMap[T]) sortedEntries() []slotEntry {
+	entries := make([]slotEntry, 0, m.size)
+	m.eachSlot(func(entry slotEntry) {
+		entries = append(entries, entry)
+	})
+	slices.SortFunc(entries, compareSlotEntries)
+	return entries
+}
+
+// smallestEntries returns up to limit entries with the smallest keys in ascending key order,
+// keeping only limit entries in a max heap while scanning
+func (m *FastMap[T]) smallestEntries(limit int) []slotEntry {
+	top := make(slotEntryHeap, 0, limit)
+	m.eachSlot(func(entry slotEntry) {
+		if len(top) < limit {
+			heap.Push(&top, entry)
+			return
+		}
+		if entry.key < top[0].key {
+			top[0] = entry
+			heap.Fix(&top, 0)
+		}
+	})
+	result := []slotEntry(top)
+	slices.SortFunc(result, compareSlotEntries)
+	return result
+}
+
+// slotEntryHeap is a max heap of entries by key
+type slotEntryHeap []slotEntry
+
+func (h slotEntryHeap) Len() int           { return len(h) }
+func (h slotEntryHeap) Less(i, j int) bool { return h[i].key > h[j].key }
+func (h slotEntryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *slotEntryHeap) Push(x any)        { *h = append(*h, x.(slotEntry)) }
+func (h *slotEntryHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// MarshalJSON encodes the map as a JSON object with decimal string keys in ascending order.
+func (m *FastMap[T]) MarshalJSON() ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	buffer.WriteByte('{')
+	for i, entry := range m.sortedEntries() {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.WriteByte('"')
+		buffer.WriteString(strconv.FormatInt(entry.key, 10))
+		buffer.WriteString(`":`)
+		data, err := json.Marshal(m.slotValue(entry))
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(data)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON adds entries of a JSON object with decimal string keys to the map.
+// A zero FastMap is initialized with the 0.75 fill factor.
+func (m *FastMap[T]) UnmarshalJSON(data []byte) error {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	if m.keys == nil {
+		*m = *NewFastMap[T](max(len(entries), 1), 0.75)
+	}
+	m.Reserve(m.size + len(entries))
+	for k, raw := range entries {
+		key, err := strconv.ParseInt(k, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid FastMap key %q: %w", k, err)
+		}
+		var val T
+		if err = json.Unmarshal(raw, &val); err != nil {
+			return err
+		}
+		m.Put(key, val)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the map with EncodeBinary, so that maps work with encoding/gob.
+func (m *FastMap[T]) MarshalBinary() ([]byte, error) {
+	return encodeBytes(m)
+}
+
+// UnmarshalBinary decodes the map encoded with MarshalBinary.
+func (m *FastMap[T]) UnmarshalBinary(data []byte) error {
+	buffer := readers.Get()
+	defer readers.Put(buffer)
+	if err := buffer.FromBytes(data); err != nil {
+		return err
+	}
+	return m.DecodeBinary(buffer)
+}
+
+// String returns a preview of up to stringPreviewLimit entries with the smallest keys, formatted like a Go map.
+func (m *FastMap[T]) String() string {
+	buffer := new(bytes.Buffer)
+	buffer.WriteString("map[")
+	for i, entry := range m.smallestEntries(stringPreviewLimit) {
+		if i > 0 {
+			buffer.WriteByte(' ')
+		}
+		fmt.Fprintf(buffer, "%d:%v", entry.key, m.slotValue(entry))
+	}
+	if more := m.size - stringPreviewLimit; more > 0 {
+		fmt.Fprintf(buffer, " +%d more", more)
+	}
+	buffer.WriteByte(']')
+	return buffer.String()
+}
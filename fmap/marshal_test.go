@@ -0,0 +1,85 @@
+package fmap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFastMap_MarshalJSON(t *testing.T) {
+	m := NewFastMap[codecPoint](4, 0.75)
+	m.Put(10, codecPoint{X: 1, ID: 1})
+	m.Put(-2, codecPoint{X: 2, ID: 2})
+	m.Put(0, codecPoint{ID: 3})
+	data, err := json.Marshal(m)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"-2":{"X":2,"Y":0,"ID":2},"0":{"X":0,"Y":0,"ID":3},"10":{"X":1,"Y":0,"ID":1}}`, string(data))
+
+	decoded := &FastMap[codecPoint]{}
+	assert.Nil(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, 3, decoded.Size())
+	for _, key := range []int64{10, -2, 0} {
+		expect, _ := m.Get(key)
+		actual, ok := decoded.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, expect, actual)
+	}
+	assert.NotNil(t, json.Unmarshal([]byte(`{"x":{}}`), decoded))
+
+	var holder struct {
+		Counts *FastMap[int] `json:"counts"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`{"counts":{"5":50,"6":60}}`), &holder))
+	val, ok := holder.Counts.Get(6)
+	assert.True(t, ok)
+	assert.Equal(t, 60, val)
+}
+
+func TestFastMap_MarshalBinary(t *testing.T) {
+	m := NewFastMap[string](4, 0.75, WithRobinHood())
+	for i := int64(0); i < 100; i++ {
+		m.Put(i, "v")
+	}
+	buffer := new(bytes.Buffer)
+	assert.Nil(t, gob.NewEncoder(buffer).Encode(m))
+	decoded := &FastMap[string]{}
+	assert.Nil(t, gob.NewDecoder(buffer).Decode(decoded))
+	assert.Equal(t, 100, decoded.Size())
+	assert.Equal(t, m.String(), decoded.String())
+}
+
+func TestFastMap_String(t *testing.T) {
+	m := NewFastMap[int](4, 0.75)
+	assert.Equal(t, "map[]", m.String())
+	m.Put(3, 30)
+	m.Put(-1, 10)
+	assert.Equal(t, "map[-1:10 3:30]", m.String())
+	for i := int64(0); i < 20; i++ {
+		m.Put(i, int(i))
+	}
+	assert.Equal(t, "map[-1:10 0:0 1:1 2:2 3:3 4:4 5:5 6:6 7:7 8:8 9:9 10:10 11:11 12:12 13:13 14:14 +5 more]", m.String())
+}
+
+// TestFastMap_String_Preview verifies that the preview holds the smallest keys regardless of slot order.
+func TestFastMap_String_Preview(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[int](4, 0.75, mode.opts...)
+			for i := int64(1000); i > -1000; i -= 7 {
+				m.Put(i*7919, int(i))
+			}
+			expect := "map["
+			for i, entry := range m.sortedEntries()[:stringPreviewLimit] {
+				if i > 0 {
+					expect += " "
+				}
+				expect += fmt.Sprintf("%d:%d", entry.key, entry.key/7919)
+			}
+			expect += fmt.Sprintf(" +%d more]", m.Size()-stringPreviewLimit)
+			assert.Equal(t, expect, m.String())
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package fmap
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+)
+
+const (
+	multiNil    int32 = -1   // Run index terminating a chain of runs
+	multiMinRun       = 2    // Capacity of the first run of a key
+	multiMaxRun       = 1024 // Maximum capacity of a run
+)
+
+// multiRun represents a contiguous run of values of one key in the arena
+type multiRun struct {
+	offset int32 // Position of the run in the arena
+	len    int32
+	cap    int32
+	next   int32 // Next run of the same key
+}
+
+// multiHead represents the chain of runs holding values of one key
+type multiHead struct {
+	first int32
+	last  int32
+	count int32
+}
+
+// newMultiHead returns an empty chain of runs
+func newMultiHead() multiHead {
+	return multiHead{first: multiNil, last: multiNil}
+}
+
+// MultiMap is a hash map of int64 keys to multiple values.
+// Values of all keys share one arena slice; each key owns a chain of runs in the arena, with every new run
+// doubling the capacity of the key up to multiMaxRun, so a key with n values takes O(log n) runs.
+// Removed keys leave their runs unused until Compact; the arena holds up to math.MaxInt32 values.
+// This implementation is not safe for concurrent use.
+type MultiMap[T any] struct {
+	index   *FastMap[multiHead]
+	runs    []multiRun
+	arena   []T
+	size    int // Number of values
+	garbage int // Number of arena slots owned by removed keys
+}
+
+// Append adds the value to values of the key.
+func (m *MultiMap[T]) Append(key int64, value T) {
+	head, _, _ := m.index.GetOrInsert(key, newMultiHead)
+	if head.last == multiNil || m.runs[head.last].len == m.runs[head.last].cap {
+		r := m.allocateRun(min(max(int(head.count), multiMinRun), multiMaxRun))
+		if head.last == multiNil {
+			head.first = r
+		} else {
+			m.runs[head.last].next = r
+		}
+		head.last = r
+	}
+	run := &m.runs[head.last]
+	m.arena[run.offset+run.len] = value
+	run.len++
+	head.count++
+	m.size++
+}
+
+// Values returns an iterator over values of the key in the order they were appended.
+func (m *MultiMap[T]) Values(key int64) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		head, ok := m.index.Get(key)
+		if !ok {
+			return
+		}
+		for r := head.first; r != multiNil; r = m.runs[r].next {
+			run := m.runs[r]
+			for _, value := range m.arena[run.offset : run.offset+run.len] {
+				if !yield(value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Count returns the number of values of the key.
+func (m *MultiMap[T]) Count(key int64) int {
+	head, _ := m.index.Get(key)
+	return int(head.count)
+}
+
+// RemoveKey removes all values of the key and returns the number of removed values.
+func (m *MultiMap[T]) RemoveKey(key int64) int {
+	head, ok := m.index.Delete(key)
+	if !ok {
+		return 0
+	}
+	for r := head.first; r != multiNil; r = m.runs[r].next {
+		run := m.runs[r]
+		clear(m.arena[run.offset : run.offset+run.len])
+		m.garbage += int(run.cap)
+	}
+	m.size -= int(head.count)
+	return int(head.count)
+}
+
+// Compact rebuilds the arena without runs of removed keys, storing values of every key in a single run.
+func (m *MultiMap[T]) Compact() {
+	arena := make([]T, 0, m.size)
+	runs := make([]multiRun, 0, m.index.Size())
+	m.heads(func(head *multiHead) {
+		run := multiRun{offset: int32(len(arena)), len: head.count, cap: head.count, next: multiNil}
+		for r := head.first; r != multiNil; r = m.runs[r].next {
+			prev := m.runs[r]
+			arena = append(arena, m.arena[prev.offset:prev.offset+prev.len]...)
+		}
+		head.first = int32(len(runs))
+		head.last = head.first
+		runs = append(runs, run)
+	})
+	m.arena = arena
+	m.runs = runs
+	m.garbage = 0
+}
+
+// Size returns the number of keys in the map.
+func (m *MultiMap[T]) Size() int {
+	if m == nil {
+		return 0
+	}
+	return m.index.Size()
+}
+
+// Len returns the number of values of all keys.
+func (m *MultiMap[T]) Len() int {
+	if m == nil {
+		return 0
+	}
+	return m.size
+}
+
+// Garbage returns the number of arena slots left by removed keys, which Compact reclaims.
+func (m *MultiMap[T]) Garbage() int {
+	return m.garbage
+}
+
+// heads calls fn with the chain of runs of every key
+func (m *MultiMap[T]) heads(fn func(head *multiHead)) {
+	index := m.index
+	if index.hasFreeKey {
+		fn(&index.freeVal)
+	}
+	for i, key := range index.keys {
+		if key != FREE_KEY {
+			fn(&index.data[i])
+		}
+	}
+}
+
+// allocateRun appends a run with the given capacity to the arena and returns its index.
+// It panics when the arena would exceed math.MaxInt32 values, which int32 run offsets cannot address.
+func (m *MultiMap[T]) allocateRun(capacity int) int32 {
+	offset := len(m.arena)
+	if offset+capacity > math.MaxInt32 {
+		panic(fmt.Sprintf("MultiMap arena exceeds %v values", math.MaxInt32))
+	}
+	m.arena = slices.Grow(m.arena, capacity)[:offset+capacity]
+	m.runs = append(m.runs, multiRun{offset: int32(offset), cap: int32(capacity), next: multiNil})
+	return int32(len(m.runs) - 1)
+}
+
+// NewMultiMap creates a new MultiMap with the specified expected number of keys, fill factor and options.
+func NewMultiMap[T any](expectedKeys int, fillFactor float64, opts ...Option) *MultiMap[T] {
+	rejectOffHeap("MultiMap", opts)
+	return &MultiMap[T]{index: NewFastMap[multiHead](expectedKeys, fillFactor, opts...)}
+}
@@ -0,0 +1,115 @@
+package fmap
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestMultiMap(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewMultiMap[int](4, 0.75, mode.opts...)
+			for i := 0; i < 100; i++ {
+				m.Append(int64(i%3), i)
+			}
+			m.Append(7, 70)
+			assert.Equal(t, 4, m.Size())
+			assert.Equal(t, 101, m.Len())
+			assert.Equal(t, 34, m.Count(0))
+			assert.Equal(t, 0, m.Count(5))
+			assert.Empty(t, slices.Collect(m.Values(5)))
+			values := slices.Collect(m.Values(1))
+			assert.Equal(t, 33, len(values))
+			for i, value := range values {
+				assert.Equal(t, 1+i*3, value)
+			}
+
+			assert.Equal(t, 34, m.RemoveKey(0))
+			assert.Equal(t, 0, m.RemoveKey(0))
+			assert.Equal(t, 67, m.Len())
+			assert.True(t, m.Garbage() > 0)
+			m.Compact()
+			assert.Equal(t, 0, m.Garbage())
+			assert.Equal(t, 67, len(m.arena))
+			assert.Equal(t, values, slices.Collect(m.Values(1)))
+			m.Append(1, 1000)
+			assert.Equal(t, append(values, 1000), slices.Collect(m.Values(1)))
+			assert.Equal(t, []int{70}, slices.Collect(m.Values(7)))
+
+			buffer := new(bytes.Buffer)
+			_, err := m.WriteTo(buffer)
+			assert.Nil(t, err)
+			decoded := &MultiMap[int]{}
+			_, err = decoded.ReadFrom(bytes.NewReader(buffer.Bytes()))
+			assert.Nil(t, err)
+			assert.Equal(t, m.Len(), decoded.Len())
+			assert.Equal(t, m.Size(), decoded.Size())
+			for _, key := range []int64{0, 1, 2, 7} {
+				assert.Equal(t, slices.Collect(m.Values(key)), slices.Collect(decoded.Values(key)), key)
+			}
+			decoded.Append(2, -1)
+			assert.Equal(t, -1, slices.Collect(decoded.Values(2))[33])
+		})
+	}
+}
+
+func TestMultiMap_DecodeBinary_Corrupted(t *testing.T) {
+	for name, corrupt := range map[string]func(m *MultiMap[int]){
+		"negative next": func(m *MultiMap[int]) { m.runs[0].next = -2 },
+		"cycle": func(m *MultiMap[int]) {
+			head, _ := m.index.GetPointer(1)
+			m.runs[head.last].next = head.first
+		},
+		"shared run": func(m *MultiMap[int]) {
+			first, _ := m.index.GetPointer(1)
+			second, _ := m.index.GetPointer(2)
+			*second = *first
+		},
+		"head count": func(m *MultiMap[int]) {
+			head, _ := m.index.GetPointer(1)
+			head.count++
+		},
+		"head last": func(m *MultiMap[int]) {
+			head, _ := m.index.GetPointer(1)
+			head.last = head.first
+		},
+		"head first": func(m *MultiMap[int]) {
+			head, _ := m.index.GetPointer(2)
+			head.first = int32(len(m.runs))
+		},
+		"size": func(m *MultiMap[int]) { m.size++ },
+	} {
+		t.Run(name, func(t *testing.T) {
+			m := NewMultiMap[int](4, 0.75)
+			for i := 0; i < 10; i++ {
+				m.Append(1, i)
+			}
+			m.Append(2, 20)
+			corrupt(m)
+			buffer := new(bytes.Buffer)
+			_, err := m.WriteTo(buffer)
+			assert.Nil(t, err)
+			receiver := NewMultiMap[int](4, 0.75)
+			receiver.Append(5, 50)
+			_, err = receiver.ReadFrom(bytes.NewReader(buffer.Bytes()))
+			if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), "corrupted MultiMap stream")
+			}
+			for i := 0; i < 100; i++ {
+				receiver.Append(5, i)
+			}
+			assert.Equal(t, 1, receiver.Size(), "receiver is left unchanged")
+			assert.Equal(t, 101, receiver.Count(5))
+			assert.Equal(t, 0, receiver.Count(1))
+		})
+	}
+}
+
+func TestMultiMap_ArenaLimit(t *testing.T) {
+	m := NewMultiMap[struct{}](4, 0.75)
+	m.arena = make([]struct{}, math.MaxInt32-1)
+	assert.Panics(t, func() { m.Append(1, struct{}{}) })
+}
@@ -0,0 +1,50 @@
+package fmap
+
+import "unsafe"
+
+// allocate replaces slots with empty slots of the given capacity.
+// Off-heap slots share one region: keys, values and control bytes, each aligned to 8 bytes;
+// the caller releases the previous region once its slots are no longer needed.
+func (m *FastMap[T]) allocate(capacity int) {
+	hasCtrl := m.probing == groupProbing
+	if !m.offHeap {
+		m.keys = make([]int64, capacity)
+		m.data = make([]T, capacity)
+		if hasCtrl {
+			m.ctrl = newCtrl(capacity)
+		}
+		return
+	}
+	var zero T
+	valueSize := int(unsafe.Sizeof(zero))
+	dataOffset := capacity * 8
+	ctrlOffset := align8(dataOffset + capacity*valueSize)
+	size := ctrlOffset
+	if hasCtrl {
+		size += capacity
+	}
+	m.region = allocRegion(size)
+	m.keys = unsafe.Slice((*int64)(unsafe.Pointer(&m.region[0])), capacity)
+	if valueSize > 0 {
+		m.data = unsafe.Slice((*T)(unsafe.Pointer(&m.region[dataOffset])), capacity)
+	} else {
+		m.data = make([]T, capacity)
+	}
+	if hasCtrl {
+		m.ctrl = m.region[ctrlOffset:size]
+		for i := range m.ctrl {
+			m.ctrl[i] = ctrlEmpty
+		}
+	}
+}
+
+// Free releases off-heap slots of the map created with WithOffHeap; the map must not be used afterwards.
+// It does nothing for maps allocated on the heap.
+func (m *FastMap[T]) Free() {
+	if m.region == nil {
+		return
+	}
+	region := m.region
+	*m = FastMap[T]{}
+	releaseRegion(region)
+}
@@ -0,0 +1,76 @@
+package fmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFastMap_OffHeap(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewFastMap[codecPoint](4, 0.75, append(mode.opts, WithOffHeap())...)
+			assert.NotNil(t, m.region)
+			for i := int64(0); i < 10000; i++ {
+				m.Put(i, codecPoint{X: float32(i), ID: i})
+			}
+			for i := int64(0); i < 10000; i += 2 {
+				m.Delete(i)
+			}
+			c := m.Clone()
+			m.Free()
+			assert.Nil(t, m.region)
+			assert.Equal(t, 0, m.Size())
+			assert.Equal(t, 5000, c.Size())
+			for i := int64(0); i < 10000; i++ {
+				val, ok := c.Get(i)
+				assert.Equal(t, i%2 == 1, ok)
+				if ok {
+					assert.Equal(t, i, val.ID)
+				}
+			}
+			c.Free()
+		})
+	}
+	assert.Panics(t, func() {
+		NewFastMap[string](4, 0.75, WithOffHeap())
+	})
+	m := NewFastMap[int](4, 0.75)
+	m.Free()
+	assert.Equal(t, 0, m.Size())
+}
+
+func TestWithOffHeap_Unsupported(t *testing.T) {
+	for name, create := range map[string]func(){
+		"AtomicMap":  func() { NewAtomicMap[int](4, 0.75, WithOffHeap()) },
+		"CounterMap": func() { NewCounterMap[int](4, 0.75, WithOffHeap()) },
+		"FastSet":    func() { NewFastSet(4, 0.75, WithOffHeap()) },
+		"MultiMap":   func() { NewMultiMap[int](4, 0.75, WithOffHeap()) },
+		"OrderedMap": func() { NewOrderedMap[int](4, 0.75, WithOffHeap()) },
+		"PairMap":    func() { NewPairMap[int](4, 0.75, WithOffHeap()) },
+		"ShardedMap": func() { NewShardedMap[int](2, 4, 0.75, WithOffHeap()) },
+		"TripleMap":  func() { NewTripleMap[int](4, 0.75, WithOffHeap()) },
+	} {
+		assert.PanicsWithValue(t, name+" does not support WithOffHeap", create, name)
+	}
+}
+
+func TestFastMap_OffHeap_DecodeBinary(t *testing.T) {
+	src := NewFastMap[int64](4, 0.75)
+	for i := int64(0); i < 100; i++ {
+		src.Put(i, i)
+	}
+	data, err := src.MarshalBinary()
+	if !assert.Nil(t, err) {
+		return
+	}
+	m := NewFastMap[int64](4, 0.75, WithOffHeap())
+	m.Put(1000, 1000)
+	assert.Nil(t, m.UnmarshalBinary(data))
+	assert.False(t, m.offHeap)
+	assert.Nil(t, m.region)
+	for i := int64(0); i < 1000; i++ {
+		m.Put(i, -i)
+	}
+	assert.Equal(t, 1000, m.Size())
+	m.Free()
+}
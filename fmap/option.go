@@ -0,0 +1,91 @@
+package fmap
+
+// probing defines collision resolution strategy
+type probing uint8
+
+const (
+	linearProbing    probing = iota // Plain linear probing, the default
+	robinHoodProbing                // Linear probing with Robin Hood insertion
+	groupProbing                    // Swiss table style probing over groups of control bytes
+)
+
+// options represents FastMap construction options
+type options struct {
+	probing probing
+	hasher  *hasher
+	offHeap bool
+}
+
+// Option represents FastMap construction option
+type Option func(o *options)
+
+// WithRobinHood returns an option enabling Robin Hood insertion.
+// Entries with longer probe distance displace entries closer to their home slot, which bounds the variance
+// of probe distances at high fill factors and lets lookups for missing keys exit early.
+func WithRobinHood() Option {
+	return func(o *options) {
+		o.probing = robinHoodProbing
+	}
+}
+
+// WithGroupProbing returns an option enabling Swiss table style group probing.
+// Every slot has a control byte holding a 7-bit hash tag; lookups compare a group of 8 control bytes at once,
+// so most misses are resolved without touching keys.
+func WithGroupProbing() Option {
+	return func(o *options) {
+		o.probing = groupProbing
+	}
+}
+
+// WithHasher returns an option replacing phiMix with a custom key hash function.
+// Serialized maps record only the name, so any process loading them, including the one that built them,
+// has to register the same function under the name with RegisterHasher first.
+func WithHasher(name string, fn func(int64) uint64) Option {
+	if name == "" || fn == nil {
+		panic("hasher name and function are required")
+	}
+	return func(o *options) {
+		o.hasher = &hasher{kind: registeredHasher, name: name, fn: fn}
+	}
+}
+
+// WithSeed returns an option using the built-in seeded mixer with the given seed.
+// Structured keys, i.e. multiples of 2^16, which cluster with phiMix, are spread uniformly.
+func WithSeed(seed uint64) Option {
+	return func(o *options) {
+		o.hasher = newSeededHasher(seed)
+	}
+}
+
+// WithRandomSeed returns an option using the built-in seeded mixer with a random seed, which resists hash flooding.
+func WithRandomSeed() Option {
+	return func(o *options) {
+		o.hasher = newSeededHasher(randomSeed())
+	}
+}
+
+// WithOffHeap returns an option placing keys, values and control bytes in one manually managed memory region
+// outside the Go heap, so the garbage collector neither scans nor copies them. The map has to be released with Free,
+// and so does every Clone of it. Only NewFastMap supports the option; constructors of maps built on FastMap,
+// which have no Free, panic when given it, and so does NewFastMap when T contains pointers.
+func WithOffHeap() Option {
+	return func(o *options) {
+		o.offHeap = true
+	}
+}
+
+// rejectOffHeap panics when opts enable WithOffHeap for the named map, which cannot release off-heap slots
+func rejectOffHeap(name string, opts []Option) {
+	if newOptions(opts).offHeap {
+		panic(name + " does not support WithOffHeap")
+	}
+}
+
+// newOptions applies opts to default options
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,180 @@
+package fmap
+
+const orderedNil int32 = -1 // Entry index terminating the insertion order list
+
+// OrderedMap is a hash map of int64 keys iterating in insertion order.
+// A FastMap indexes entries stored in parallel keys and values slices, which are linked in insertion order by
+// prev and next entry indexes; removed entries are reused through a free list, so Put, Get and Delete stay O(1).
+// This implementation is not safe for concurrent use.
+type OrderedMap[T any] struct {
+	index  *FastMap[int32]
+	keys   []int64
+	values []T
+	prev   []int32
+	next   []int32
+	head   int32 // Oldest entry
+	tail   int32 // Newest entry
+	free   int32 // First reusable entry, free entries are linked by next
+}
+
+// Get retrieves the value associated with the given key.
+// It returns the value and a boolean indicating whether the key was found.
+func (m *OrderedMap[T]) Get(key int64) (T, bool) {
+	if idx, ok := m.index.Get(key); ok {
+		return m.values[idx], true
+	}
+	var zero T
+	return zero, false
+}
+
+// GetPointer retrieves the pointer to the value associated with the given key.
+// The pointer is valid until the next Put of a new key.
+func (m *OrderedMap[T]) GetPointer(key int64) (*T, bool) {
+	if idx, ok := m.index.Get(key); ok {
+		return &m.values[idx], true
+	}
+	return nil, false
+}
+
+// Put adds the key with the value val at the end of the insertion order, or updates the value of an existing key
+// keeping its position.
+func (m *OrderedMap[T]) Put(key int64, val T) {
+	idx := orderedNil
+	ptr, found, _ := m.index.GetOrInsert(key, func() int32 {
+		idx = m.allocate()
+		return idx
+	})
+	if found {
+		m.values[*ptr] = val
+		return
+	}
+	m.keys[idx] = key
+	m.values[idx] = val
+	m.linkBack(idx)
+}
+
+// Delete removes the key from the map.
+// It returns the removed value and a boolean indicating whether the key was found.
+func (m *OrderedMap[T]) Delete(key int64) (T, bool) {
+	var zero T
+	idx, ok := m.index.Delete(key)
+	if !ok {
+		return zero, false
+	}
+	val := m.values[idx]
+	m.unlink(idx)
+	m.keys[idx] = FREE_KEY
+	m.values[idx] = zero
+	m.next[idx] = m.free
+	m.free = idx
+	return val, true
+}
+
+// First returns the oldest entry; the third return value is false if the map is empty.
+func (m *OrderedMap[T]) First() (int64, T, bool) {
+	return m.entry(m.head)
+}
+
+// Last returns the newest entry; the third return value is false if the map is empty.
+func (m *OrderedMap[T]) Last() (int64, T, bool) {
+	return m.entry(m.tail)
+}
+
+// MoveToBack moves the key to the end of the insertion order and returns false if the key was not found.
+func (m *OrderedMap[T]) MoveToBack(key int64) bool {
+	idx, ok := m.index.Get(key)
+	if !ok {
+		return false
+	}
+	if idx != m.tail {
+		m.unlink(idx)
+		m.linkBack(idx)
+	}
+	return true
+}
+
+// Iterator returns a function iterating over entries in insertion order; the third return value is false once exhausted.
+// The entry just returned may be deleted while iterating.
+func (m *OrderedMap[T]) Iterator() func() (int64, T, bool) {
+	idx := m.head
+	return func() (int64, T, bool) {
+		key, val, ok := m.entry(idx)
+		if ok {
+			idx = m.next[idx]
+		}
+		return key, val, ok
+	}
+}
+
+// Size returns the number of elements in the map.
+func (m *OrderedMap[T]) Size() int {
+	if m == nil {
+		return 0
+	}
+	return m.index.Size()
+}
+
+// entry returns the key and value of the entry at idx
+func (m *OrderedMap[T]) entry(idx int32) (int64, T, bool) {
+	if idx == orderedNil {
+		var zero T
+		return 0, zero, false
+	}
+	return m.keys[idx], m.values[idx], true
+}
+
+// allocate returns an unlinked entry index, reusing removed entries first
+func (m *OrderedMap[T]) allocate() int32 {
+	if idx := m.free; idx != orderedNil {
+		m.free = m.next[idx]
+		return idx
+	}
+	var zero T
+	m.keys = append(m.keys, FREE_KEY)
+	m.values = append(m.values, zero)
+	m.prev = append(m.prev, orderedNil)
+	m.next = append(m.next, orderedNil)
+	return int32(len(m.keys) - 1)
+}
+
+// linkBack appends the entry at idx to the end of the insertion order
+func (m *OrderedMap[T]) linkBack(idx int32) {
+	m.prev[idx] = m.tail
+	m.next[idx] = orderedNil
+	if m.tail == orderedNil {
+		m.head = idx
+	} else {
+		m.next[m.tail] = idx
+	}
+	m.tail = idx
+}
+
+// unlink removes the entry at idx from the insertion order
+func (m *OrderedMap[T]) unlink(idx int32) {
+	prev, next := m.prev[idx], m.next[idx]
+	if prev == orderedNil {
+		m.head = next
+	} else {
+		m.next[prev] = next
+	}
+	if next == orderedNil {
+		m.tail = prev
+	} else {
+		m.prev[next] = prev
+	}
+}
+
+// NewOrderedMap creates a new OrderedMap with the specified expected size, fill factor and options.
+func NewOrderedMap[T any](expectedSize int, fillFactor float64, opts ...Option) *OrderedMap[T] {
+	rejectOffHeap("OrderedMap", opts)
+	return &OrderedMap[T]{
+		index:  NewFastMap[int32](expectedSize, fillFactor, opts...),
+		keys:   make([]int64, 0, expectedSize),
+		values: make([]T, 0, expectedSize),
+		prev:   make([]int32, 0, expectedSize),
+		next:   make([]int32, 0, expectedSize),
+		head:   orderedNil,
+		tail:   orderedNil,
+		free:   orderedNil,
+	}
+}
@@ -0,0 +1,61 @@
+package fmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// orderedKeys returns keys of the map in iteration order
+func orderedKeys[T any](m *OrderedMap[T]) []int64 {
+	var keys []int64
+	next := m.Iterator()
+	for key, _, ok := next(); ok; key, _, ok = next() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestOrderedMap(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			m := NewOrderedMap[string](2, 0.75, mode.opts...)
+			_, _, ok := m.First()
+			assert.False(t, ok)
+			for _, key := range []int64{30, 0, 10, 20, -5} {
+				m.Put(key, "v")
+			}
+			m.Put(10, "updated")
+			assert.Equal(t, []int64{30, 0, 10, 20, -5}, orderedKeys(m))
+			val, ok := m.Get(10)
+			assert.True(t, ok)
+			assert.Equal(t, "updated", val)
+
+			val, ok = m.Delete(0)
+			assert.True(t, ok)
+			assert.Equal(t, "v", val)
+			_, ok = m.Delete(0)
+			assert.False(t, ok)
+			assert.True(t, m.MoveToBack(30))
+			assert.False(t, m.MoveToBack(99))
+			m.Put(40, "reused")
+			assert.Equal(t, []int64{10, 20, -5, 30, 40}, orderedKeys(m))
+			assert.Equal(t, 5, m.Size())
+
+			key, val, ok := m.First()
+			assert.True(t, ok)
+			assert.EqualValues(t, 10, key)
+			assert.Equal(t, "updated", val)
+			key, val, ok = m.Last()
+			assert.True(t, ok)
+			assert.EqualValues(t, 40, key)
+			assert.Equal(t, "reused", val)
+
+			next := m.Iterator()
+			for key, _, ok := next(); ok; key, _, ok = next() {
+				m.Delete(key)
+			}
+			assert.Equal(t, 0, m.Size())
+			assert.Empty(t, orderedKeys(m))
+		})
+	}
+}
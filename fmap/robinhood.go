@@ -0,0 +1,101 @@
+package fmap
+
+import "sync/atomic"
+
+// distance returns how far the key stored at ptr is from its home slot
+func (m *FastMap[T]) distance(key int64, ptr int64) int64 {
+	return (ptr - m.hash(key)&m.mask) & m.mask
+}
+
+// findRobinHood returns the slot holding the key, or -1 if the key is absent.
+func (m *FastMap[T]) findRobinHood(key int64) int64 {
+	if ptr, found := m.locateRobinHood(key); found {
+		return ptr
+	}
+	return -1
+}
+
+// locateRobinHood returns the slot holding the key, or the slot where the key belongs if absent.
+// The search stops as soon as it reaches an entry closer to its home slot than the key would be,
+// since Robin Hood insertion would have placed the key before such an entry.
+func (m *FastMap[T]) locateRobinHood(key int64) (int64, bool) {
+	ptr := m.hash(key) & m.mask
+	for dist := int64(0); ; dist++ {
+		k := m.keys[ptr]
+		if k == key {
+			return ptr, true
+		}
+		if k == FREE_KEY || m.distance(k, ptr) < dist {
+			return ptr, false
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+}
+
+// getRobinHood retrieves the value associated with the given non FREE_KEY key.
+func (m *FastMap[T]) getRobinHood(key int64) (T, bool) {
+	if ptr := m.findRobinHood(key); ptr >= 0 {
+		return m.data[ptr], true
+	}
+	var zero T
+	return zero, false
+}
+
+// putRobinHood adds or updates the given non FREE_KEY key.
+func (m *FastMap[T]) putRobinHood(key int64, val T) {
+	ptr, found := m.locateRobinHood(key)
+	if found {
+		m.data[ptr] = val
+		return
+	}
+	m.insertRobinHood(ptr, key, val)
+}
+
+// insertRobinHood places the key at the slot returned by locateRobinHood and reports whether other entries moved.
+// The entry occupying the slot and the rest of its run shift one slot forward, which keeps the run ordered by home slot.
+func (m *FastMap[T]) insertRobinHood(ptr int64, key int64, val T) bool {
+	moved := m.shiftForward(ptr)
+	atomic.AddUint32(&m.scn, 1) //added new key
+	m.keys[ptr] = key
+	m.data[ptr] = val
+	m.size++
+	if m.size >= m.threshold {
+		m.rehash()
+	}
+	return moved
+}
+
+// shiftForward moves entries from ptr up to the next empty slot one slot forward, leaving ptr free.
+// It reports whether any entry moved.
+func (m *FastMap[T]) shiftForward(ptr int64) bool {
+	end := ptr
+	for m.keys[end] != FREE_KEY {
+		end = (end + 1) & m.mask
+	}
+	moved := end != ptr
+	for end != ptr {
+		prev := (end - 1) & m.mask
+		m.keys[end] = m.keys[prev]
+		m.data[end] = m.data[prev]
+		end = prev
+	}
+	return moved
+}
+
+// removeRobinHood empties the occupied slot at ptr.
+// Following entries that are not in their home slot shift one slot back, so no tombstones are needed.
+func (m *FastMap[T]) removeRobinHood(ptr int64) {
+	var zero T
+	for {
+		next := (ptr + 1) & m.mask
+		k := m.keys[next]
+		if k == FREE_KEY || m.distance(k, next) == 0 {
+			break
+		}
+		m.keys[ptr] = k
+		m.data[ptr] = m.data[next]
+		ptr = next
+	}
+	m.keys[ptr] = FREE_KEY
+	m.data[ptr] = zero
+}
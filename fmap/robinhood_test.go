@@ -0,0 +1,98 @@
+package fmap
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// TestFastMap_Probing tests Put, Get and Delete with every probing strategy against a reference map.
+func TestFastMap_Probing(t *testing.T) {
+	testCases := []struct {
+		name       string
+		fillFactor float64
+		keys       func(i int64) int64
+	}{
+		{name: "Sequential", fillFactor: 0.75, keys: func(i int64) int64 { return i }},
+		{name: "Clustered", fillFactor: 0.875, keys: func(i int64) int64 { return i << 16 }},
+		{name: "Random", fillFactor: 0.875, keys: func(i int64) int64 { return rand.New(rand.NewSource(i)).Int63() }},
+	}
+	for _, mode := range probingModes {
+		for _, tc := range testCases {
+			t.Run(mode.name+"/"+tc.name, func(t *testing.T) {
+				m := NewFastMap[int64](4, tc.fillFactor, mode.opts...)
+				expect := map[int64]int64{}
+				for i := int64(0); i < 5000; i++ {
+					key := tc.keys(i)
+					m.Put(key, i)
+					expect[key] = i
+					if i%3 == 0 {
+						deleted := tc.keys(i / 2)
+						_, ok := m.Delete(deleted)
+						_, expectOk := expect[deleted]
+						if ok != expectOk {
+							t.Fatalf("Test %s: Expected delete %d=%v, got %v", tc.name, deleted, expectOk, ok)
+						}
+						delete(expect, deleted)
+					}
+				}
+				if m.Size() != len(expect) {
+					t.Errorf("Test %s: Expected size=%d, got %d", tc.name, len(expect), m.Size())
+				}
+				for i := int64(0); i < 6000; i++ {
+					key := tc.keys(i)
+					val, ok := m.Get(key)
+					expectVal, expectOk := expect[key]
+					if ok != expectOk || val != expectVal {
+						t.Errorf("Test %s: Key %d: expected %d, %v, got %d, %v", tc.name, key, expectVal, expectOk, val, ok)
+					}
+				}
+				if m.probing != robinHoodProbing {
+					return
+				}
+				for ptr, key := range m.keys {
+					if key == FREE_KEY {
+						continue
+					}
+					next := m.keys[(int64(ptr)+1)&m.mask]
+					if next != FREE_KEY && m.distance(next, (int64(ptr)+1)&m.mask) > m.distance(key, int64(ptr))+1 {
+						t.Fatalf("Test %s: Robin Hood invariant violated at slot %d", tc.name, ptr)
+					}
+				}
+			})
+		}
+	}
+}
+
+// newBenchmarkMap returns a map with 65536 slots filled just below the fill factor, and keys not in the map
+func newBenchmarkMap(fillFactor float64, opts ...Option) (*FastMap[int64], []int64, []int64) {
+	m := NewFastMap[int64](1<<15, fillFactor, opts...)
+	random := rand.New(rand.NewSource(1))
+	count := m.threshold - 1
+	keys := make([]int64, count)
+	missing := make([]int64, count)
+	for i := range keys {
+		keys[i] = random.Int63()
+		missing[i] = random.Int63()
+		m.Put(keys[i], int64(i))
+	}
+	return m, keys, missing
+}
+
+func BenchmarkFastMap_Probing(b *testing.B) {
+	for _, fillFactor := range []float64{0.5, 0.75, 0.9} {
+		for _, mode := range probingModes {
+			m, keys, missing := newBenchmarkMap(fillFactor, mode.opts...)
+			b.Run(fmt.Sprintf("%s/Hit/%v", mode.name, fillFactor), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					m.Get(keys[i%len(keys)])
+				}
+			})
+			b.Run(fmt.Sprintf("%s/Miss/%v", mode.name, fillFactor), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					m.Get(missing[i%len(missing)])
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package fmap
+
+import (
+	"sort"
+)
+
+// FastSet is a high-performance hash set of int64 keys.
+// It shares FastMap probing and hashing; values are zero size, so only the keys array takes memory.
+// This implementation is not safe for concurrent use.
+type FastSet struct {
+	m    *FastMap[struct{}]
+	opts []Option
+}
+
+// Add adds the key to the set and returns true if the key was not present.
+func (s *FastSet) Add(key int64) bool {
+	m := s.m
+	if key == FREE_KEY {
+		if m.hasFreeKey {
+			return false
+		}
+		m.putFreeKey(struct{}{})
+		return true
+	}
+	ptr, found := m.locate(key)
+	if found {
+		return false
+	}
+	m.insertAt(ptr, key, struct{}{})
+	return true
+}
+
+// Contains returns true if the set contains the key.
+func (s *FastSet) Contains(key int64) bool {
+	if key == FREE_KEY {
+		return s.m.hasFreeKey
+	}
+	return s.m.find(key) >= 0
+}
+
+// Remove removes the key from the set and returns true if the key was present.
+func (s *FastSet) Remove(key int64) bool {
+	_, ok := s.m.Delete(key)
+	return ok
+}
+
+// Cardinality returns the number of keys in the set.
+func (s *FastSet) Cardinality() int {
+	if s == nil {
+		return 0
+	}
+	return s.m.Size()
+}
+
+// each calls fn for every key until fn returns false
+func (s *FastSet) each(fn func(key int64) bool) {
+	m := s.m
+	if m.hasFreeKey && !fn(FREE_KEY) {
+		return
+	}
+	for _, key := range m.keys {
+		if key != FREE_KEY && !fn(key) {
+			return
+		}
+	}
+}
+
+// Sorted returns the set keys in ascending order.
+func (s *FastSet) Sorted() []int64 {
+	keys := make([]int64, 0, s.Cardinality())
+	s.each(func(key int64) bool {
+		keys = append(keys, key)
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// newSet creates an empty set with the same options as the receiver
+func (s *FastSet) newSet(expectedSize int) *FastSet {
+	return NewFastSet(max(expectedSize, 1), s.m.fillFactor, s.opts...)
+}
+
+// Union returns a new set with keys present in either set.
+func (s *FastSet) Union(other *FastSet) *FastSet {
+	result := s.newSet(s.Cardinality() + other.Cardinality())
+	for _, set := range []*FastSet{s, other} {
+		set.each(func(key int64) bool {
+			result.Add(key)
+			return true
+		})
+	}
+	return result
+}
+
+// Intersect returns a new set with keys present in both sets.
+func (s *FastSet) Intersect(other *FastSet) *FastSet {
+	smaller, larger := s, other
+	if smaller.Cardinality() > larger.Cardinality() {
+		smaller, larger = larger, smaller
+	}
+	result := s.newSet(smaller.Cardinality())
+	smaller.each(func(key int64) bool {
+		if larger.Contains(key) {
+			result.Add(key)
+		}
+		return true
+	})
+	return result
+}
+
+// Difference returns a new set with keys present in this set but not in the other.
+func (s *FastSet) Difference(other *FastSet) *FastSet {
+	result := s.newSet(s.Cardinality())
+	s.each(func(key int64) bool {
+		if !other.Contains(key) {
+			result.Add(key)
+		}
+		return true
+	})
+	return result
+}
+
+// IsSubset returns true if every key of this set is present in the other set.
+func (s *FastSet) IsSubset(other *FastSet) bool {
+	if s.Cardinality() > other.Cardinality() {
+		return false
+	}
+	subset := true
+	s.each(func(key int64) bool {
+		subset = other.Contains(key)
+		return subset
+	})
+	return subset
+}
+
+// NewFastSet creates a new FastSet with the specified expected size, fill factor and options.
+func NewFastSet(expectedSize int, fillFactor float64, opts ...Option) *FastSet {
+	rejectOffHeap("FastSet", opts)
+	return &FastSet{m: NewFastMap[struct{}](expectedSize, fillFactor, opts...), opts: opts}
+}
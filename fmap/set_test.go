@@ -0,0 +1,43 @@
+package fmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// newTestSet returns a set with the given keys
+func newTestSet(opts []Option, keys ...int64) *FastSet {
+	s := NewFastSet(4, 0.75, opts...)
+	for _, key := range keys {
+		s.Add(key)
+	}
+	return s
+}
+
+func TestFastSet(t *testing.T) {
+	for _, mode := range probingModes {
+		t.Run(mode.name, func(t *testing.T) {
+			s := NewFastSet(4, 0.75, mode.opts...)
+			assert.True(t, s.Add(0))
+			assert.True(t, s.Add(5))
+			assert.False(t, s.Add(5))
+			assert.True(t, s.Contains(0))
+			assert.True(t, s.Contains(5))
+			assert.False(t, s.Contains(6))
+			assert.True(t, s.Remove(0))
+			assert.False(t, s.Remove(0))
+			assert.Equal(t, 1, s.Cardinality())
+
+			a := newTestSet(mode.opts, 0, 1, 2, 3, 100)
+			b := newTestSet(mode.opts, 2, 3, 4, -7)
+			assert.Equal(t, []int64{-7, 0, 1, 2, 3, 4, 100}, a.Union(b).Sorted())
+			assert.Equal(t, []int64{2, 3}, a.Intersect(b).Sorted())
+			assert.Equal(t, []int64{0, 1, 100}, a.Difference(b).Sorted())
+			assert.Equal(t, []int64{-7, 4}, b.Difference(a).Sorted())
+			assert.True(t, a.Intersect(b).IsSubset(a))
+			assert.True(t, a.Intersect(b).IsSubset(b))
+			assert.False(t, a.IsSubset(b))
+			assert.Equal(t, 0, a.Intersect(newTestSet(mode.opts)).Cardinality())
+		})
+	}
+}
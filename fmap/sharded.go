@@ -0,0 +1,144 @@
+package fmap
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// cacheLineSize is the assumed CPU cache line size
+const cacheLineSize = 64
+
+// shardPadding pads the lock and the map pointer of a shard to a multiple of cacheLineSize
+const shardPadding = (cacheLineSize - (unsafe.Sizeof(sync.RWMutex{})+unsafe.Sizeof(uintptr(0)))%cacheLineSize) % cacheLineSize
+
+// shard represents a single FastMap guarded by its own lock
+type shard[T any] struct {
+	sync.RWMutex
+	m *FastMap[T]
+	_ [shardPadding]byte // padding to keep shard locks on separate cache lines
+}
+
+// ShardedMap is a concurrent hash map for int64 keys.
+// It spreads keys over independent FastMap shards selected by the high bits of a key hash,
+// each shard guarded by its own read/write lock, so goroutines only contend on the same shard.
+type ShardedMap[T any] struct {
+	shards []shard[T]
+	shift  uint // Shift extracting shard index from the high hash bits
+}
+
+// shardFor returns the shard owning the key
+func (s *ShardedMap[T]) shardFor(key int64) *shard[T] {
+	return &s.shards[mix64(uint64(key))>>s.shift]
+}
+
+// Get retrieves the value associated with the given key.
+// It returns the value and a boolean indicating whether the key was found.
+func (s *ShardedMap[T]) Get(key int64) (T, bool) {
+	sh := s.shardFor(key)
+	sh.RLock()
+	val, ok := sh.m.Get(key)
+	sh.RUnlock()
+	return val, ok
+}
+
+// Put adds or updates the key with the value val.
+func (s *ShardedMap[T]) Put(key int64, val T) {
+	sh := s.shardFor(key)
+	sh.Lock()
+	sh.m.Put(key, val)
+	sh.Unlock()
+}
+
+// Delete removes the key from the map.
+// It returns the removed value and a boolean indicating whether the key was found.
+func (s *ShardedMap[T]) Delete(key int64) (T, bool) {
+	sh := s.shardFor(key)
+	sh.Lock()
+	val, ok := sh.m.Delete(key)
+	sh.Unlock()
+	return val, ok
+}
+
+// Size returns the number of elements in the map.
+// Shards are counted one at a time, so concurrent writes may not be reflected.
+func (s *ShardedMap[T]) Size() int {
+	if s == nil {
+		return 0
+	}
+	size := 0
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.RLock()
+		size += sh.m.Size()
+		sh.RUnlock()
+	}
+	return size
+}
+
+// Shards returns the number of shards
+func (s *ShardedMap[T]) Shards() int {
+	return len(s.shards)
+}
+
+// Iterator returns a function iterating over entries of all shards; the third return value is false once exhausted.
+// Each shard is copied under its read lock when the iterator reaches it, so every shard is seen consistently,
+// while writes to shards not yet visited are reflected.
+func (s *ShardedMap[T]) Iterator() func() (int64, T, bool) {
+	shardIndex := 0
+	var keys []int64
+	var values []T
+	i := 0
+	return func() (int64, T, bool) {
+		for i >= len(keys) {
+			if shardIndex >= len(s.shards) {
+				var zero T
+				return 0, zero, false
+			}
+			keys, values, i = keys[:0], values[:0], 0
+			sh := &s.shards[shardIndex]
+			shardIndex++
+			sh.RLock()
+			next := sh.m.Iterator()
+			for {
+				k, v, hasMore := next()
+				if !hasMore {
+					break
+				}
+				keys = append(keys, int64(k))
+				values = append(values, v)
+			}
+			sh.RUnlock()
+		}
+		k, v := keys[i], values[i]
+		i++
+		return k, v, true
+	}
+}
+
+// NewShardedMap creates a new ShardedMap with the specified number of shards, expected size and fill factor.
+// The number of shards is rounded up to a power of two, and the expected size is split evenly between shards.
+// Options are applied to every shard.
+func NewShardedMap[T any](shards int, expectedSize int, fillFactor float64, opts ...Option) *ShardedMap[T] {
+	if shards <= 0 {
+		panic("Shards must be positive")
+	}
+	rejectOffHeap("ShardedMap", opts)
+	if expectedSize <= 0 {
+		panic("Size must be positive")
+	}
+	count := int(nextPowerOf2(uint64(shards)))
+	perShard := (expectedSize + count - 1) / count
+	s := &ShardedMap[T]{
+		shards: make([]shard[T], count),
+		shift:  uint(64 - bitsTrailingZeros(uint64(count))),
+	}
+	for i := range s.shards {
+		s.shards[i].m = NewFastMap[T](perShard, fillFactor, opts...)
+	}
+	return s
+}
+
+// bitsTrailingZeros returns the number of trailing zeros in a power of two x.
+func bitsTrailingZeros(x uint64) int {
+	return 63 - bitsLeadingZeros(x)
+}
@@ -0,0 +1,74 @@
+package fmap
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// TestShardedMap_Concurrent tests concurrent Put, Get and Delete on ShardedMap.
+func TestShardedMap_Concurrent(t *testing.T) {
+	testCases := []struct {
+		name      string
+		shards    int
+		workers   int
+		perWorker int
+	}{
+		{name: "SingleShard", shards: 1, workers: 4, perWorker: 1000},
+		{name: "RoundedShards", shards: 6, workers: 8, perWorker: 2000},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewShardedMap[int64](tc.shards, 16, 0.75)
+			var wg sync.WaitGroup
+			for w := 0; w < tc.workers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					base := int64(w * tc.perWorker)
+					for i := int64(0); i < int64(tc.perWorker); i++ {
+						m.Put(base+i, i)
+					}
+					for i := int64(0); i < int64(tc.perWorker); i += 2 {
+						if _, ok := m.Delete(base + i); !ok {
+							t.Errorf("Expected key %d to be deleted", base+i)
+						}
+					}
+					for i := int64(0); i < int64(tc.perWorker); i++ {
+						val, ok := m.Get(base + i)
+						if ok != (i%2 == 1) || (ok && val != i) {
+							t.Errorf("Key %d: unexpected value %d, %v", base+i, val, ok)
+						}
+					}
+				}(w)
+			}
+			wg.Wait()
+			expectSize := tc.workers * tc.perWorker / 2
+			if m.Size() != expectSize {
+				t.Errorf("Test %s: Expected size=%d, got %d", tc.name, expectSize, m.Size())
+			}
+			count := 0
+			next := m.Iterator()
+			for {
+				k, v, hasMore := next()
+				if !hasMore {
+					break
+				}
+				if k%int64(tc.perWorker) != v {
+					t.Errorf("Test %s: unexpected entry %d: %d", tc.name, k, v)
+				}
+				count++
+			}
+			if count != expectSize {
+				t.Errorf("Test %s: Expected %d iterated entries, got %d", tc.name, expectSize, count)
+			}
+		})
+	}
+}
+
+// TestShardedMap_Padding verifies that shards occupy whole cache lines.
+func TestShardedMap_Padding(t *testing.T) {
+	if size := unsafe.Sizeof(shard[int]{}); size%cacheLineSize != 0 {
+		t.Errorf("Expected shard size to be a multiple of %d, got %d", cacheLineSize, size)
+	}
+}
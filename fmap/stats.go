@@ -0,0 +1,91 @@
+package fmap
+
+import "unsafe"
+
+// StatsHistogramSize is the number of probe length histogram buckets; the last bucket counts all longer probes.
+const StatsHistogramSize = 64
+
+// Stats represents FastMap occupancy and probing diagnostics
+type Stats struct {
+	Size            int                     // Number of elements, including FREE_KEY
+	Capacity        int                     // Number of slots
+	LoadFactor      float64                 // Ratio of occupied slots to capacity
+	FillFactor      float64                 // Configured fill factor
+	ProbeHistogram  [StatsHistogramSize]int // Number of keys per displacement from the home slot (home group for group probing)
+	MaxDisplacement int                     // Longest displacement from the home slot
+	AvgDisplacement float64                 // Average displacement from the home slot
+	LongestRun      int                     // Longest run of consecutive occupied slots
+	Tombstones      int                     // Number of deleted slots still occupying space, group probing only
+	Rehashes        int                     // Number of times slots were rehashed
+	BytesInUse      int                     // Estimated bytes used by slots, excluding memory referenced by values
+}
+
+// Stats returns map diagnostics computed in a single pass over keys.
+func (m *FastMap[T]) Stats() Stats {
+	var zero T
+	capacity := len(m.keys)
+	stats := Stats{
+		Size:       m.size,
+		Capacity:   capacity,
+		FillFactor: m.fillFactor,
+		Tombstones: m.tombstones,
+		Rehashes:   int(m.rehashes),
+		BytesInUse: int(unsafe.Sizeof(*m)) + capacity*(int(unsafe.Sizeof(FREE_KEY))+int(unsafe.Sizeof(zero))) + len(m.ctrl),
+	}
+	occupied, total := 0, 0
+	run, firstRun := 0, -1
+	for ptr, key := range m.keys {
+		if key == FREE_KEY {
+			if firstRun < 0 {
+				firstRun = run
+			}
+			run = 0
+			continue
+		}
+		occupied++
+		run++
+		if run > stats.LongestRun {
+			stats.LongestRun = run
+		}
+		displacement := m.displacement(key, int64(ptr))
+		total += displacement
+		if displacement > stats.MaxDisplacement {
+			stats.MaxDisplacement = displacement
+		}
+		if displacement >= StatsHistogramSize {
+			displacement = StatsHistogramSize - 1
+		}
+		stats.ProbeHistogram[displacement]++
+	}
+	if firstRun > 0 && run > 0 && firstRun+run > stats.LongestRun { // run wrapping around the end of keys
+		stats.LongestRun = firstRun + run
+	}
+	if capacity > 0 {
+		stats.LoadFactor = float64(occupied+m.tombstones) / float64(capacity)
+	}
+	if occupied > 0 {
+		stats.AvgDisplacement = float64(total) / float64(occupied)
+	}
+	return stats
+}
+
+// displacement returns the number of probe steps from the key home position to ptr
+func (m *FastMap[T]) displacement(key int64, ptr int64) int {
+	if m.probing != groupProbing {
+		return int((ptr - m.hash(key)&m.mask) & m.mask)
+	}
+	groupMask := uint64(m.mask) / groupSize
+	target := uint64(ptr) / groupSize
+	g := (uint64(m.hash(key)) >> 7) & groupMask
+	steps := 0
+	for g != target {
+		steps++
+		g = (g + uint64(steps)) & groupMask
+	}
+	return steps
+}
+
+// Stats returns map diagnostics computed in a single pass over keys.
+func (m *MappedMap[T]) Stats() Stats {
+	return m.m.Stats()
+}
@@ -0,0 +1,59 @@
+package fmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFastMap_Stats(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "Linear"},
+		{name: "RobinHood", opts: []Option{WithRobinHood()}},
+		{name: "Group", opts: []Option{WithGroupProbing()}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewFastMap[int64](4, 0.75, tc.opts...)
+			for i := int64(0); i < 1000; i++ {
+				m.Put(i<<16, i)
+			}
+			stats := m.Stats()
+			assert.Equal(t, 1000, stats.Size)
+			assert.Equal(t, m.Cap(), stats.Capacity)
+			assert.InDelta(t, float64(999)/float64(m.Cap()), stats.LoadFactor, 0.0001)
+			assert.Greater(t, stats.Rehashes, 0)
+			assert.GreaterOrEqual(t, stats.LongestRun, 1)
+			assert.GreaterOrEqual(t, float64(stats.MaxDisplacement), stats.AvgDisplacement)
+			assert.Greater(t, stats.BytesInUse, m.Cap()*16)
+			count := 0
+			for _, n := range stats.ProbeHistogram {
+				count += n
+			}
+			assert.Equal(t, 999, count)
+		})
+	}
+}
+
+func TestFastMap_StatsLongestRun(t *testing.T) {
+	m := NewFastMap[int](4, 0.75)
+	m.keys = []int64{1, 1, 0, 1, 0, 0, 1, 1}
+	assert.Equal(t, 4, m.Stats().LongestRun)
+	m.keys = []int64{1, 1, 1, 0, 0, 0, 0, 1}
+	assert.Equal(t, 4, m.Stats().LongestRun)
+	m.keys = []int64{0, 1, 1, 1, 0, 0, 0, 0}
+	assert.Equal(t, 3, m.Stats().LongestRun)
+}
+
+func TestFastMap_StatsAllocs(t *testing.T) {
+	m := NewFastMap[int](1024, 0.75)
+	for i := int64(0); i < 500; i++ {
+		m.Put(i, int(i))
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = m.Stats()
+	})
+	assert.Equal(t, float64(0), allocs)
+}
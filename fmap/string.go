@@ -0,0 +1,219 @@
+package fmap
+
+import (
+	"math"
+	"sync/atomic"
+	"unsafe"
+)
+
+const (
+	stringLenBits = 24                           // Bits of a slot used for key length
+	stringMaxLen  = 1<<stringLenBits - 1         // Maximum supported key length
+	stringUsedBit = uint64(1) << 63              // Marks a cached hash of an occupied slot
+	fnvOffset64   = uint64(14695981039346656037) // FNV-1a offset basis
+	fnvPrime64    = uint64(1099511628211)        // FNV-1a prime
+)
+
+// hashString returns FNV-1a hash of the key, finalized with mix64 and flagged with stringUsedBit, so it is never zero.
+func hashString(key string) uint64 {
+	h := fnvOffset64
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= fnvPrime64
+	}
+	return mix64(h) | stringUsedBit
+}
+
+// StringMap is a high-performance hash map for string keys.
+// It uses the same open addressing with linear probing as FastMap, but stores all keys in one contiguous
+// byte arena; each slot keeps the key offset and length packed into uint64 and a cached key hash,
+// where zero hash denotes an empty slot. Rehashing reuses cached hashes and never copies the arena.
+// This implementation is not safe for concurrent use.
+type StringMap[T any] struct {
+	arena      []byte   // Append-only key storage
+	slots      []uint64 // Key offset << stringLenBits | key length
+	hashes     []uint64 // Cached key hashes, zero for empty slot
+	data       []T      // Array of values corresponding to keys
+	fillFactor float64  // Fill factor for resizing the map
+	threshold  int      // Resize threshold based on computeCapacity and fill factor
+	size       int      // Number of elements in the map
+	cap        uint32
+	mask       uint64 // Mask for calculating indices during probing
+	scn        uint32
+}
+
+// key returns the key stored in the slot at ptr.
+// The arena is append-only, so the returned string shares its memory and stays valid even after the arena grows.
+func (m *StringMap[T]) key(ptr uint64) string {
+	slot := m.slots[ptr]
+	offset := slot >> stringLenBits
+	length := slot & stringMaxLen
+	if length == 0 {
+		return ""
+	}
+	return unsafe.String(&m.arena[offset], int(length))
+}
+
+// matches returns true if the slot at ptr holds the key
+func (m *StringMap[T]) matches(ptr uint64, key string) bool {
+	slot := m.slots[ptr]
+	offset := slot >> stringLenBits
+	length := slot & stringMaxLen
+	return int(length) == len(key) && string(m.arena[offset:offset+length]) == key
+}
+
+// Get retrieves the value associated with the given key.
+// It returns the value and a boolean indicating whether the key was found.
+func (m *StringMap[T]) Get(key string) (T, bool) {
+	var zero T
+	h := hashString(key)
+	ptr := h & m.mask
+	for {
+		kh := m.hashes[ptr]
+		if kh == 0 {
+			return zero, false
+		}
+		if kh == h && m.matches(ptr, key) {
+			return m.data[ptr], true
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+}
+
+// GetPointer retrieves the value pointer associated with the given key.
+// It returns the value and a boolean indicating whether the key was found.
+func (m *StringMap[T]) GetPointer(key string) (*T, bool) {
+	h := hashString(key)
+	ptr := h & m.mask
+	for {
+		kh := m.hashes[ptr]
+		if kh == 0 {
+			return nil, false
+		}
+		if kh == h && m.matches(ptr, key) {
+			return &m.data[ptr], true
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+}
+
+// SCN returns the current sequence number of the map
+func (m *StringMap[T]) SCN() int {
+	return int(atomic.LoadUint32(&m.scn))
+}
+
+// Put adds or updates the key with the value val.
+// It panics if the key is longer than 16MB.
+func (m *StringMap[T]) Put(key string, val T) {
+	if len(key) > stringMaxLen {
+		panic("key length exceeds maximum supported length")
+	}
+	h := hashString(key)
+	ptr := h & m.mask
+	for {
+		kh := m.hashes[ptr]
+		if kh == 0 {
+			break
+		}
+		if kh == h && m.matches(ptr, key) {
+			m.data[ptr] = val
+			return
+		}
+		ptr = (ptr + 1) & m.mask
+	}
+	atomic.AddUint32(&m.scn, 1) //added new key
+	offset := uint64(len(m.arena))
+	m.arena = append(m.arena, key...)
+	m.slots[ptr] = offset<<stringLenBits | uint64(len(key))
+	m.hashes[ptr] = h
+	m.data[ptr] = val
+	m.size++
+	if m.size >= m.threshold {
+		m.rehash()
+	}
+}
+
+// Value returns the key and value at the given pointer.
+func (m *StringMap[T]) Value(ptr *int) (string, T, bool) {
+	for *ptr < len(m.hashes) {
+		i := uint64(*ptr)
+		*ptr++
+		if m.hashes[i] != 0 {
+			return m.key(i), m.data[i], true
+		}
+	}
+	var zero T
+	return "", zero, false
+}
+
+// Iterator returns a function iterating over all map entries; the third return value is false once exhausted.
+func (m *StringMap[T]) Iterator() func() (string, T, bool) {
+	i := 0
+	return func() (string, T, bool) {
+		return m.Value(&i)
+	}
+}
+
+// rehash doubles the computeCapacity and reinserts all slots using cached hashes.
+func (m *StringMap[T]) rehash() {
+	atomic.AddUint32(&m.scn, 1)
+	newCapacity := len(m.hashes) * 2
+	m.mask = uint64(newCapacity - 1)
+	m.threshold = int(math.Floor(float64(newCapacity) * m.fillFactor))
+	m.cap = uint32(newCapacity)
+	oldSlots := m.slots
+	oldHashes := m.hashes
+	oldData := m.data
+	m.slots = make([]uint64, newCapacity)
+	m.hashes = make([]uint64, newCapacity)
+	m.data = make([]T, newCapacity)
+	for i, h := range oldHashes {
+		if h == 0 {
+			continue
+		}
+		ptr := h & m.mask
+		for m.hashes[ptr] != 0 {
+			ptr = (ptr + 1) & m.mask
+		}
+		m.hashes[ptr] = h
+		m.slots[ptr] = oldSlots[i]
+		m.data[ptr] = oldData[i]
+	}
+}
+
+// Size returns the number of elements in the map.
+func (m *StringMap[T]) Size() int {
+	if m == nil {
+		return 0
+	}
+	return m.size
+}
+
+// Cap returns the computeCapacity of the map.
+func (m *StringMap[T]) Cap() int {
+	if m == nil {
+		return 0
+	}
+	return int(atomic.LoadUint32(&m.cap))
+}
+
+// NewStringMap creates a new StringMap with the specified expected size and fill factor.
+// The fill factor must be between 0 and 1 (exclusive), and determines when the map will be resized.
+func NewStringMap[T any](expectedSize int, fillFactor float64) *StringMap[T] {
+	if fillFactor <= 0 || fillFactor >= 1 {
+		panic("FillFactor must be in (0, 1)")
+	}
+	if expectedSize <= 0 {
+		panic("Size must be positive")
+	}
+	capacity := computeCapacity(expectedSize, fillFactor)
+	return &StringMap[T]{
+		slots:      make([]uint64, capacity),
+		hashes:     make([]uint64, capacity),
+		data:       make([]T, capacity),
+		fillFactor: fillFactor,
+		threshold:  int(math.Floor(float64(capacity) * fillFactor)),
+		mask:       uint64(capacity - 1),
+		cap:        uint32(capacity),
+	}
+}
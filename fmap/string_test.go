@@ -0,0 +1,75 @@
+package fmap
+
+import (
+	"strconv"
+	"testing"
+)
+
+// TestStringMap_DataDriven tests the StringMap using a data-driven approach.
+func TestStringMap_DataDriven(t *testing.T) {
+	testCases := []struct {
+		name        string
+		key         string
+		value       int
+		expectGet   int
+		expectSize  int
+		expectFound bool
+	}{
+		{name: "InsertEmpty", key: "", value: 1, expectGet: 1, expectSize: 1, expectFound: true},
+		{name: "InsertFoo", key: "foo", value: 2, expectGet: 2, expectSize: 2, expectFound: true},
+		{name: "InsertBar", key: "bar", value: 3, expectGet: 3, expectSize: 3, expectFound: true},
+		{name: "UpdateFoo", key: "foo", value: 4, expectGet: 4, expectSize: 3, expectFound: true},
+	}
+
+	m := NewStringMap[int](2, 0.75)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m.Put(tc.key, tc.value)
+			val, found := m.Get(tc.key)
+			if found != tc.expectFound {
+				t.Errorf("Test %s: Expected found=%v, got %v", tc.name, tc.expectFound, found)
+			}
+			if val != tc.expectGet {
+				t.Errorf("Test %s: Expected value=%v, got %v", tc.name, tc.expectGet, val)
+			}
+			if m.Size() != tc.expectSize {
+				t.Errorf("Test %s: Expected size=%d, got %d", tc.name, tc.expectSize, m.Size())
+			}
+		})
+	}
+	if _, found := m.Get("baz"); found {
+		t.Errorf("Expected missing key not to be found")
+	}
+}
+
+// TestStringMap_Rehash tests rehashing and iteration of the StringMap.
+func TestStringMap_Rehash(t *testing.T) {
+	m := NewStringMap[int](4, 0.75)
+	for i := 0; i < 10000; i++ {
+		m.Put("token"+strconv.Itoa(i), i)
+	}
+	if m.Size() != 10000 {
+		t.Errorf("Expected size=%d, got %d", 10000, m.Size())
+	}
+	for i := 0; i < 10000; i++ {
+		ptr, found := m.GetPointer("token" + strconv.Itoa(i))
+		if !found || *ptr != i {
+			t.Fatalf("Expected token%d with value %d, got %v", i, i, found)
+		}
+	}
+	seen := 0
+	next := m.Iterator()
+	for {
+		k, v, hasMore := next()
+		if !hasMore {
+			break
+		}
+		if k != "token"+strconv.Itoa(v) {
+			t.Errorf("Unexpected entry %s: %d", k, v)
+		}
+		seen++
+	}
+	if seen != m.Size() {
+		t.Errorf("Expected %d iterated entries, got %d", m.Size(), seen)
+	}
+}
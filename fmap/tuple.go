@@ -0,0 +1,281 @@
+package fmap
+
+import (
+	"encoding/binary"
+	"iter"
+)
+
+// tupleIndex represents an index of packed composite keys to entries
+type tupleIndex[K comparable] interface {
+	Get(key K) (int32, bool)
+	GetOrInsert(key K, fn func() int32) (*int32, bool, bool)
+	Delete(key K) (int32, bool)
+	Size() int
+}
+
+// tupleRow represents the list of entries sharing the first key component
+type tupleRow struct {
+	head  int32
+	tail  int32
+	count int32
+}
+
+// tupleMap stores entries of composite keys in parallel slices, indexed by the packed key.
+// Entries sharing the first component are linked in insertion order by prev and next entry indexes,
+// and the secondary rows index maps the first component to its list, so a row is iterated without a scan.
+type tupleMap[K comparable, T any] struct {
+	index  tupleIndex[K]
+	rows   *FastMap[tupleRow]
+	keys   []K
+	values []T
+	prev   []int32
+	next   []int32
+	free   int32 // First reusable entry, free entries are linked by next
+}
+
+// get retrieves the value associated with the packed key
+func (m *tupleMap[K, T]) get(key K) (T, bool) {
+	if idx, ok := m.index.Get(key); ok {
+		return m.values[idx], true
+	}
+	var zero T
+	return zero, false
+}
+
+// put adds or updates the packed key of the row
+func (m *tupleMap[K, T]) put(key K, row int32, val T) {
+	slot, found, _ := m.index.GetOrInsert(key, m.allocate)
+	idx := *slot
+	m.values[idx] = val
+	if found {
+		return
+	}
+	m.keys[idx] = key
+	r, _, _ := m.rows.GetOrInsert(int64(row), func() tupleRow {
+		return tupleRow{head: orderedNil, tail: orderedNil}
+	})
+	m.prev[idx] = r.tail
+	m.next[idx] = orderedNil
+	if r.tail == orderedNil {
+		r.head = idx
+	} else {
+		m.next[r.tail] = idx
+	}
+	r.tail = idx
+	r.count++
+}
+
+// delete removes the packed key of the row
+func (m *tupleMap[K, T]) delete(key K, row int32) (T, bool) {
+	var zero T
+	idx, ok := m.index.Delete(key)
+	if !ok {
+		return zero, false
+	}
+	val := m.values[idx]
+	r, _ := m.rows.GetPointer(int64(row))
+	if r.count--; r.count == 0 {
+		m.rows.Delete(int64(row))
+	} else {
+		prev, next := m.prev[idx], m.next[idx]
+		if prev == orderedNil {
+			r.head = next
+		} else {
+			m.next[prev] = next
+		}
+		if next == orderedNil {
+			r.tail = prev
+		} else {
+			m.prev[next] = prev
+		}
+	}
+	m.values[idx] = zero
+	m.next[idx] = m.free
+	m.free = idx
+	return val, true
+}
+
+// row returns an iterator over packed keys and values of the row in insertion order
+func (m *tupleMap[K, T]) row(row int32) iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		r, ok := m.rows.Get(int64(row))
+		if !ok {
+			return
+		}
+		for idx := r.head; idx != orderedNil; idx = m.next[idx] {
+			if !yield(m.keys[idx], m.values[idx]) {
+				return
+			}
+		}
+	}
+}
+
+// Rows returns an iterator over distinct first key components.
+func (m *tupleMap[K, T]) Rows() iter.Seq[int32] {
+	return func(yield func(int32) bool) {
+		for row := range m.rows.Keys() {
+			if !yield(int32(row)) {
+				return
+			}
+		}
+	}
+}
+
+// RowSize returns the number of entries with the given first key component.
+func (m *tupleMap[K, T]) RowSize(row int32) int {
+	r, _ := m.rows.Get(int64(row))
+	return int(r.count)
+}
+
+// Size returns the number of elements in the map.
+func (m *tupleMap[K, T]) Size() int {
+	return m.index.Size()
+}
+
+// allocate returns an unused entry index, reusing removed entries first
+func (m *tupleMap[K, T]) allocate() int32 {
+	if idx := m.free; idx != orderedNil {
+		m.free = m.next[idx]
+		return idx
+	}
+	var key K
+	var zero T
+	m.keys = append(m.keys, key)
+	m.values = append(m.values, zero)
+	m.prev = append(m.prev, orderedNil)
+	m.next = append(m.next, orderedNil)
+	return int32(len(m.keys) - 1)
+}
+
+// newTupleMap creates a tupleMap with the given index, options apply to the rows index
+func newTupleMap[K comparable, T any](index tupleIndex[K], expectedSize int, fillFactor float64, opts ...Option) tupleMap[K, T] {
+	return tupleMap[K, T]{
+		index: index,
+		rows:  NewFastMap[tupleRow](expectedSize, fillFactor, opts...),
+		free:  orderedNil,
+	}
+}
+
+// pairHasherName is the name of the hasher registered for packed pairs
+const pairHasherName = builtinHasherPrefix + "pair"
+
+func init() {
+	registerHasher(pairHasherName, pairMix)
+}
+
+// pairMix hashes a packed pair; unlike phiMix it spreads the high 32 bits over the low bits used for slots
+func pairMix(key int64) uint64 {
+	return mix64(uint64(key))
+}
+
+// packPair packs a pair into int64; the (0, 0) pair becomes FREE_KEY, which FastMap stores separately
+func packPair(a, b int32) int64 {
+	return int64(uint64(uint32(a))<<32 | uint64(uint32(b)))
+}
+
+// PairMap is a hash map keyed by (int32, int32) pairs, such as (tenant, item) or (row, col).
+// Pairs are packed into int64 keys hashed with a dedicated mixer, and entries sharing the first component
+// form a row that is iterated with a secondary index.
+// This implementation is not safe for concurrent use.
+type PairMap[T any] struct {
+	tupleMap[int64, T]
+}
+
+// Get retrieves the value associated with the pair.
+// It returns the value and a boolean indicating whether the pair was found.
+func (m *PairMap[T]) Get(a, b int32) (T, bool) {
+	return m.get(packPair(a, b))
+}
+
+// Put adds or updates the pair with the value val.
+func (m *PairMap[T]) Put(a, b int32, val T) {
+	m.put(packPair(a, b), a, val)
+}
+
+// Delete removes the pair from the map.
+// It returns the removed value and a boolean indicating whether the pair was found.
+func (m *PairMap[T]) Delete(a, b int32) (T, bool) {
+	return m.delete(packPair(a, b), a)
+}
+
+// Row returns an iterator over second components and values of pairs with the first component a, in insertion order.
+// The map must not be modified while iterating.
+func (m *PairMap[T]) Row(a int32) iter.Seq2[int32, T] {
+	return func(yield func(int32, T) bool) {
+		for key, val := range m.row(a) {
+			if !yield(int32(uint32(key)), val) {
+				return
+			}
+		}
+	}
+}
+
+// NewPairMap creates a new PairMap with the specified expected size, fill factor and options.
+// Options apply to the pair index, which uses pairMix unless an option sets another hasher, and to the rows index.
+func NewPairMap[T any](expectedSize int, fillFactor float64, opts ...Option) *PairMap[T] {
+	rejectOffHeap("PairMap", opts)
+	index := NewFastMap[int32](expectedSize, fillFactor, append([]Option{WithHasher(pairHasherName, pairMix)}, opts...)...)
+	return &PairMap[T]{tupleMap: newTupleMap[int64, T](index, expectedSize, fillFactor, opts...)}
+}
+
+// tripleKey represents a packed triple, the last 4 bytes are always zero
+type tripleKey [16]byte
+
+// packTriple packs a triple into tripleKey; the (0, 0, 0) triple becomes the zero key, which KeyMap stores separately
+func packTriple(a, b, c int32) tripleKey {
+	var key tripleKey
+	binary.LittleEndian.PutUint32(key[0:], uint32(a))
+	binary.LittleEndian.PutUint32(key[4:], uint32(b))
+	binary.LittleEndian.PutUint32(key[8:], uint32(c))
+	return key
+}
+
+// TripleMap is a hash map keyed by (int32, int32, int32) triples.
+// Triples are packed into 16 byte KeyMap keys, and entries sharing the first component form a row that is
+// iterated with a secondary index.
+// This implementation is not safe for concurrent use.
+type TripleMap[T any] struct {
+	tupleMap[tripleKey, T]
+}
+
+// Get retrieves the value associated with the triple.
+// It returns the value and a boolean indicating whether the triple was found.
+func (m *TripleMap[T]) Get(a, b, c int32) (T, bool) {
+	return m.get(packTriple(a, b, c))
+}
+
+// Put adds or updates the triple with the value val.
+func (m *TripleMap[T]) Put(a, b, c int32, val T) {
+	m.put(packTriple(a, b, c), a, val)
+}
+
+// Delete removes the triple from the map.
+// It returns the removed value and a boolean indicating whether the triple was found.
+func (m *TripleMap[T]) Delete(a, b, c int32) (T, bool) {
+	return m.delete(packTriple(a, b, c), a)
+}
+
+// Row returns an iterator over the second and third components and values of triples with the first component a,
+// in insertion order. The map must not be modified while iterating.
+func (m *TripleMap[T]) Row(a int32) iter.Seq2[[2]int32, T] {
+	return func(yield func([2]int32, T) bool) {
+		for key, val := range m.row(a) {
+			bc := [2]int32{int32(binary.LittleEndian.Uint32(key[4:])), int32(binary.LittleEndian.Uint32(key[8:]))}
+			if !yield(bc, val) {
+				return
+			}
+		}
+	}
+}
+
+// NewTripleMap creates a new TripleMap with the specified expected size, fill factor and options.
+// The triple index is a KeyMap, which always uses linear probing and MixKey, so NewTripleMap panics when options
+// select a probing strategy, a hasher or off-heap slots rather than applying them to the rows index only.
+func NewTripleMap[T any](expectedSize int, fillFactor float64, opts ...Option) *TripleMap[T] {
+	rejectOffHeap("TripleMap", opts)
+	if o := newOptions(opts); o.probing != linearProbing || o.hasher != nil {
+		panic("TripleMap does not support probing or hasher options")
+	}
+	index := NewKeyMap[tripleKey, int32](expectedSize, fillFactor)
+	return &TripleMap[T]{tupleMap: newTupleMap[tripleKey, T](index, expectedSize, fillFactor, opts...)}
+}
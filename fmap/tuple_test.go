@@ -0,0 +1,92 @@
+package fmap
+
+import (
+	"github.com/stretchr/testify/assert"
+	"slices"
+	"testing"
+)
+
+func TestPairMap(t *testing.T) {
+	m := NewPairMap[string](4, 0.75)
+	m.Put(0, 0, "origin")
+	m.Put(1, 2, "a")
+	m.Put(1, -3, "b")
+	m.Put(-1, 2, "c")
+	m.Put(1, 7, "d")
+	m.Put(1, 2, "updated")
+	assert.Equal(t, 5, m.Size())
+	val, ok := m.Get(0, 0)
+	assert.True(t, ok)
+	assert.Equal(t, "origin", val)
+	val, ok = m.Get(1, 2)
+	assert.True(t, ok)
+	assert.Equal(t, "updated", val)
+	_, ok = m.Get(2, 1)
+	assert.False(t, ok)
+
+	var cols []int32
+	for b := range m.Row(1) {
+		cols = append(cols, b)
+	}
+	assert.Equal(t, []int32{2, -3, 7}, cols)
+	assert.Equal(t, 3, m.RowSize(1))
+	assert.Equal(t, []int32{-1, 0, 1}, slices.Sorted(m.Rows()))
+
+	val, ok = m.Delete(1, -3)
+	assert.True(t, ok)
+	assert.Equal(t, "b", val)
+	_, ok = m.Delete(1, -3)
+	assert.False(t, ok)
+	m.Delete(-1, 2)
+	m.Put(1, 9, "e")
+	cols = cols[:0]
+	for b := range m.Row(1) {
+		cols = append(cols, b)
+	}
+	assert.Equal(t, []int32{2, 7, 9}, cols)
+	assert.Equal(t, []int32{0, 1}, slices.Sorted(m.Rows()))
+	assert.Equal(t, 0, m.RowSize(-1))
+}
+
+func TestPairMap_Mixing(t *testing.T) {
+	m := NewPairMap[int](1<<10, 0.75)
+	for a := int32(0); a < 1<<10; a++ {
+		m.Put(a, 5, int(a))
+	}
+	assert.True(t, m.index.(*FastMap[int32]).Stats().MaxDisplacement < 32)
+}
+
+func TestTripleMap(t *testing.T) {
+	m := NewTripleMap[int](4, 0.75)
+	m.Put(0, 0, 0, 1)
+	m.Put(3, 1, 2, 2)
+	m.Put(3, -1, -2, 3)
+	m.Put(4, 1, 2, 4)
+	val, ok := m.Get(3, -1, -2)
+	assert.True(t, ok)
+	assert.Equal(t, 3, val)
+	_, ok = m.Get(3, 2, 1)
+	assert.False(t, ok)
+	val, ok = m.Get(0, 0, 0)
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+
+	var row [][2]int32
+	for bc := range m.Row(3) {
+		row = append(row, bc)
+	}
+	assert.Equal(t, [][2]int32{{1, 2}, {-1, -2}}, row)
+	_, ok = m.Delete(3, 1, 2)
+	assert.True(t, ok)
+	assert.Equal(t, 3, m.Size())
+	assert.Equal(t, 1, m.RowSize(3))
+	m.Put(3, -1, -2, 30)
+	val, _ = m.Get(3, -1, -2)
+	assert.Equal(t, 30, val)
+	assert.Equal(t, 3, m.Size())
+
+	identity := func(key int64) uint64 { return uint64(key) }
+	for _, opt := range []Option{WithRobinHood(), WithGroupProbing(), WithSeed(1), WithHasher("test-identity", identity)} {
+		assert.Panics(t, func() { NewTripleMap[int](4, 0.75, opt) }, "options the triple index cannot apply are rejected")
+	}
+}
@@ -1,16 +1,16 @@
 module github.com/viant/gds
 
-go 1.21
+go 1.23
 
 require (
 	github.com/stretchr/testify v1.7.0
+	github.com/viant/bintly v0.2.0
 	golang.org/x/exp v0.0.0-20231127185646-65229373498e
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/viant/bintly v0.2.0 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
 